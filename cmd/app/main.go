@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -9,9 +14,11 @@ import (
 	"github.com/joho/godotenv"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/shii-park/Metasugo-Backend/internal/boardstore"
 	"github.com/shii-park/Metasugo-Backend/internal/handler"
 	"github.com/shii-park/Metasugo-Backend/internal/logger"
 	"github.com/shii-park/Metasugo-Backend/internal/middleware"
+	"github.com/shii-park/Metasugo-Backend/internal/room"
 	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
 )
 
@@ -50,12 +57,121 @@ func main() {
 		log.Fatal("Firebaseの初期化に失敗:", err)
 	}
 
-	// ゲームの初期化
-	g := sugoroku.NewGame()
-	log.Info("=== Game created ===")
+	// 盤面の版の保存先。初回起動時はtiles.jsonを最初の版として取り込む
+	if err := sugoroku.InitQuiz(); err != nil {
+		log.Fatal("クイズの読み込みに失敗:", err)
+	}
+	boardDir := os.Getenv("BOARD_STORE_DIR")
+	if boardDir == "" {
+		boardDir = "boards"
+	}
+	initialTiles, err := sugoroku.LoadTilesJSON(sugoroku.TilesJSONPath)
+	if err != nil {
+		log.Fatal("盤面の読み込みに失敗:", err)
+	}
+	boards, err := boardstore.Open(boardDir, initialTiles)
+	if err != nil {
+		log.Fatal("盤面の保存先の初期化に失敗:", err)
+	}
+
+	// 部屋の初期化(部屋ごとに独立したGameを持ち、作成時点で使う版の盤面で始める)
+	rooms := room.NewRegistry(sugoroku.NewGame)
+	rooms.SetBoardStore(boards)
+	journalDir := os.Getenv("JOURNAL_DIR")
+	if journalDir == "" {
+		journalDir = "journals"
+	}
+	if err := rooms.SetJournalDir(journalDir); err != nil {
+		log.Fatal("ジャーナルの保存先の作成に失敗:", err)
+	}
+	snapshotDir := os.Getenv("SNAPSHOT_DIR")
+	if snapshotDir == "" {
+		snapshotDir = "snapshots"
+	}
+	if err := rooms.SetSnapshotDir(snapshotDir); err != nil {
+		log.Fatal("スナップショットの保存先の作成に失敗:", err)
+	}
+	// 入力要求に答えないまま離れたプレイヤーで進行が止まらないように、回答期限を設定できる
+	var defaultOptions room.Options
+	if v := os.Getenv("DECISION_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatal("DECISION_TIMEOUT の形式が不正です:", err)
+		}
+		// 部屋の設定は秒単位なので、1秒未満や端数のある値は期限なしや別の期限にならないように拒否する
+		if d < time.Second || d%time.Second != 0 {
+			log.Fatal("DECISION_TIMEOUT は1秒以上の秒単位で指定してください: ", v)
+		}
+		defaultOptions.Timeouts = room.AllTimeouts(int(d / time.Second))
+	}
+	// デフォルトの部屋はスナップショットから復元する場合も、今のDECISION_TIMEOUTを使う
+	rooms.SetRoomTimeouts(room.DefaultRoomID, defaultOptions.Timeouts)
+
+	// 前回終了時の状態があれば復元する
+	restored, err := rooms.RestoreSnapshots()
+	if err != nil {
+		log.Fatal("スナップショットからの復元に失敗:", err)
+	}
+	log.WithField("rooms", restored).Info("=== Rooms restored from snapshots ===")
+
+	if _, err := rooms.Get(room.DefaultRoomID); err != nil {
+		if _, err := rooms.Create(room.DefaultRoomID, defaultOptions); err != nil {
+			log.Fatal("デフォルトの部屋の作成に失敗:", err)
+		}
+		log.Info("=== Default room created ===")
+	}
+
+	snapshotInterval := 30 * time.Second
+	if v := os.Getenv("SNAPSHOT_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatal("SNAPSHOT_INTERVAL の形式が不正です:", err)
+		}
+		snapshotInterval = d
+	}
+	stopSnapshot := rooms.StartAutoSnapshot(snapshotInterval)
+
+	// BOARD_WATCH_INTERVALを設定すると、盤面とクイズのファイルが更新されたときに自動で読み直す
+	stopWatch := func() {}
+	if v := os.Getenv("BOARD_WATCH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatal("BOARD_WATCH_INTERVAL の形式が不正です:", err)
+		}
+		stopWatch = rooms.WatchBoard(d)
+	}
 
 	// ルーティング設定
-	handler.SetupRoutes(router, g)
+	handler.SetupRoutes(router, rooms, boards)
 
-	router.Run() // デフォルトで :8080
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	srv := &http.Server{Addr: ":" + port, Handler: router}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("サーバーの起動に失敗:", err)
+		}
+	}()
+
+	// 終了シグナルを受け取ったら、リクエストの受け付けを止めてから状態を保存する
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	log.Info("=== Shutting down ===")
+
+	stopWatch()
+	stopSnapshot()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Error("サーバーの停止に失敗")
+	}
+	// WebSocketの接続はShutdownでは閉じないので、部屋のゲームを止めてから保存する
+	if err := rooms.Shutdown(); err != nil {
+		log.WithError(err).Error("終了時のスナップショットの保存に失敗")
+	}
 }
\ No newline at end of file
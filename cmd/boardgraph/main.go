@@ -0,0 +1,48 @@
+// boardgraph は盤面をGraphvizのDOT形式またはMermaid形式で出力する。
+//
+//	go run ./cmd/boardgraph -format dot | dot -Tsvg -o board.svg
+//	go run ./cmd/boardgraph -format mermaid -o board.mmd
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/shii-park/Metasugo-Backend/internal/board"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+func main() {
+	tilesPath := flag.String("tiles", sugoroku.TilesJSONPath, "描画する盤面のJSON")
+	format := flag.String("format", board.FormatDOT, "出力形式 (dot または mermaid)")
+	output := flag.String("o", "", "出力先のファイル(省略時は標準出力)")
+	flag.Parse()
+
+	tileMap, err := sugoroku.InitTilesFromPath(*tilesPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	tiles := make([]*sugoroku.Tile, 0, len(tileMap))
+	for _, t := range tileMap {
+		tiles = append(tiles, t)
+	}
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].Id < tiles[j].Id })
+
+	graph, err := board.Render(*format, tiles)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *output == "" {
+		fmt.Print(graph)
+		return
+	}
+	if err := os.WriteFile(*output, []byte(graph), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,55 @@
+// boardlint は盤面のJSONを検証し、見つかった問題を表示する。
+// エラーがあった場合(-strictの場合は警告でも)終了コード1で終了する。
+//
+//	go run ./cmd/boardlint -tiles tiles.json -quizzes quizzes.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+func main() {
+	tilesPath := flag.String("tiles", sugoroku.TilesJSONPath, "検証する盤面のJSON")
+	quizzesPath := flag.String("quizzes", sugoroku.QuizJSONPath, "クイズのJSON(空にするとクイズIDを検証しない)")
+	strict := flag.Bool("strict", false, "警告があった場合も失敗にする")
+	asJSON := flag.Bool("json", false, "結果をJSONで出力する")
+	flag.Parse()
+
+	tiles, err := sugoroku.LoadTilesJSON(*tilesPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var quizList []sugoroku.Quiz
+	if *quizzesPath != "" {
+		quizList, err = sugoroku.LoadQuizzes(*quizzesPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	issues := sugoroku.ValidateTiles(tiles, quizList)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(issues)
+	} else {
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		errs := len(sugoroku.ErrorIssues(issues))
+		fmt.Printf("%s: %d tiles, %d errors, %d warnings\n", *tilesPath, len(tiles), errs, len(issues)-errs)
+	}
+
+	if len(sugoroku.ErrorIssues(issues)) > 0 || (*strict && len(issues) > 0) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,76 @@
+// replay はジャーナルファイルを再生し、最終的なプレイヤーの状態を表示する。
+//
+//	go run ./cmd/replay -journal journals/default-20250101-120000.jsonl
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/game"
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// playerState は再生後のプレイヤーの状態
+type playerState struct {
+	ID          string `json:"id"`
+	Position    int    `json:"position"`
+	Money       int    `json:"money"`
+	IsMarried   bool   `json:"isMarried"`
+	HasChildren int    `json:"hasChildren"`
+	Job         string `json:"job"`
+}
+
+func main() {
+	journalPath := flag.String("journal", "", "再生するジャーナルファイル")
+	tilesPath := flag.String("tiles", sugoroku.TilesJSONPath, "記録時に使った盤面のJSON")
+	quizzesPath := flag.String("quizzes", sugoroku.QuizJSONPath, "記録時に使ったクイズのJSON")
+	flag.Parse()
+
+	if *journalPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	entries, err := journal.ReadFile(*journalPath)
+	if err != nil {
+		log.Fatal("ジャーナルの読み込みに失敗:", err)
+	}
+
+	sugoroku.QuizJSONPath = *quizzesPath
+	if err := sugoroku.InitQuiz(); err != nil {
+		log.Fatal("クイズの読み込みに失敗:", err)
+	}
+	g, err := sugoroku.NewGameFromPath(*tilesPath, 0)
+	if err != nil {
+		log.Fatal("盤面の読み込みに失敗:", err)
+	}
+
+	if _, err := game.Replay(g, entries); err != nil {
+		log.Fatal("再生に失敗:", err)
+	}
+
+	states := make([]playerState, 0)
+	for _, p := range g.GetAllPlayers() {
+		states = append(states, playerState{
+			ID:          p.Id,
+			Position:    p.Position.Id,
+			Money:       p.Money,
+			IsMarried:   p.IsMarried,
+			HasChildren: p.HasChildren,
+			Job:         p.Job,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(states); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,103 @@
+// simulate は盤面でゲームを大量に実行し、所持金の分布やマスごとの影響を表示する。
+//
+//	go run ./cmd/simulate -games 1000 -players 4 -branch random -quiz-accuracy 0.6
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	stdlog "log"
+	"os"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/simulate"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+func main() {
+	tilesPath := flag.String("tiles", sugoroku.TilesJSONPath, "盤面のJSON")
+	quizzesPath := flag.String("quizzes", sugoroku.QuizJSONPath, "クイズのJSON")
+	games := flag.Int("games", 1000, "実行するゲームの数")
+	players := flag.Int("players", 4, "1ゲームあたりのプレイヤー数")
+	seed := flag.Int64("seed", 1, "乱数のシード")
+	maxTurns := flag.Int("max-turns", 200, "ゴールしないプレイヤーを打ち切る手番数")
+	branch := flag.String("branch", simulate.DefaultStrategy.Branch, "分岐の選び方 (first, last, random)")
+	quizAccuracy := flag.Float64("quiz-accuracy", simulate.DefaultStrategy.QuizAccuracy, "クイズに正解する確率")
+	gamble := flag.String("gamble", simulate.DefaultStrategy.GambleChoice, "ギャンブルで賭ける方 (High, Low, random)")
+	bet := flag.Float64("bet", simulate.DefaultStrategy.GambleBetRatio, "ギャンブルで所持金のうち賭ける割合")
+	asJSON := flag.Bool("json", false, "結果をJSONで出力する")
+	flag.Parse()
+
+	// ゲームの進行ログは大量に出るので抑える
+	log.SetLevel(log.WarnLevel)
+	stdlog.SetOutput(io.Discard)
+
+	sugoroku.QuizJSONPath = *quizzesPath
+	if err := sugoroku.InitQuiz(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if _, err := sugoroku.NewGameFromPath(*tilesPath, 0); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	report, err := simulate.Run(simulate.Config{
+		NewGame: func() *sugoroku.Game {
+			g, _ := sugoroku.NewGameFromPath(*tilesPath, 0)
+			return g
+		},
+		Games:    *games,
+		Players:  *players,
+		Seed:     *seed,
+		MaxTurns: *maxTurns,
+		Strategy: simulate.Strategy{
+			Branch:         *branch,
+			QuizAccuracy:   *quizAccuracy,
+			GambleChoice:   *gamble,
+			GambleBetRatio: *bet,
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+		return
+	}
+	printReport(os.Stdout, report)
+}
+
+func printReport(out io.Writer, r *simulate.Report) {
+	fmt.Fprintf(out, "games: %d, players: %d, finished: %d, unfinished: %d, errors: %d\n\n", r.Games, r.Players, r.Finished, r.Unfinished, r.Errors)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "\tmean\tstddev\tmin\tp10\tp50\tp90\tmax\t")
+	for _, row := range []struct {
+		name  string
+		stats simulate.Stats
+	}{
+		{"final money", r.FinalMoney},
+		{"turns to goal", r.TurnsToGoal},
+	} {
+		s := row.stats
+		fmt.Fprintf(w, "%s\t%.0f\t%.0f\t%.0f\t%.0f\t%.0f\t%.0f\t%.0f\t\n", row.name, s.Mean, s.StdDev, s.Min, s.P10, s.P50, s.P90, s.Max)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "tile\tkind\tlandings\tper player\tavg money\terrors\t")
+	for _, t := range r.Tiles {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%.3f\t%.0f\t%d\t\n", t.TileID, t.Kind, t.Landings, t.LandingRate, t.AvgMoney, t.Errors)
+	}
+	w.Flush()
+}
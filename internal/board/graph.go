@@ -0,0 +1,225 @@
+// Package board は盤面をレビュー用の図に変換する
+package board
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// 説明文は長いと図が読みにくくなるので、この文字数で切る
+const maxDetailRunes = 24
+
+// kindColors はマスの種類ごとの塗りつぶしの色
+var kindColors = map[sugoroku.TileKind]string{
+	"profit":      "#c8e6c9",
+	"loss":        "#ffcdd2",
+	"quiz":        "#bbdefb",
+	"branch":      "#fff59d",
+	"overall":     "#d1c4e9",
+	"neighbor":    "#f8bbd0",
+	"require":     "#ffe0b2",
+	"gamble":      "#ffab91",
+	"goal":        "#80cbc4",
+	"conditional": "#b2ebf2",
+	"setStatus":   "#dcedc8",
+	"childBonus":  "#f0f4c3",
+	"advance":     "#a5d6a7",
+	"retreat":     "#ef9a9a",
+	"warp":        "#ce93d8",
+	"sequence":    "#b3e5fc",
+	"choice":      "#ffe082",
+	"lottery":     "#ffccbc",
+}
+
+const defaultColor = "#eeeeee"
+
+// Node は図の1マス分の情報
+type Node struct {
+	ID      int
+	Kind    sugoroku.TileKind
+	Effect  string // 効果の要約
+	Detail  string
+	Color   string
+	Nexts   []int
+	Prevs   []int
+	Orphans []int // prev_idsにあるが、相手のnext_idsにはない前のマス
+}
+
+// Nodes はマスを図に描くための情報に変換する
+func Nodes(tiles []*sugoroku.Tile) []Node {
+	nodes := make([]Node, 0, len(tiles))
+	for _, t := range tiles {
+		color, ok := kindColors[t.Kind()]
+		if !ok {
+			color = defaultColor
+		}
+		n := Node{
+			ID:     t.Id,
+			Kind:   t.Kind(),
+			Effect: EffectSummary(t.Effect),
+			Detail: truncate(t.Detail(), maxDetailRunes),
+			Color:  color,
+		}
+		for _, next := range t.Nexts() {
+			n.Nexts = append(n.Nexts, next.Id)
+		}
+		for _, prev := range t.Prevs() {
+			n.Prevs = append(n.Prevs, prev.Id)
+			if !linksTo(prev, t) {
+				n.Orphans = append(n.Orphans, prev.Id)
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// EffectSummary は効果の種類とパラメータを1行にまとめる。例: profit {"amount":100}
+func EffectSummary(e sugoroku.EffectType) string {
+	if e == nil {
+		return ""
+	}
+	name := reflect.TypeOf(e).Name()
+	name = strings.TrimSuffix(name, "Effect")
+	if name == "No" {
+		return ""
+	}
+	if name != "" {
+		name = strings.ToLower(name[:1]) + name[1:]
+	}
+
+	params, err := json.Marshal(e)
+	if err != nil || string(params) == "{}" || string(params) == "null" {
+		return name
+	}
+	return fmt.Sprintf("%s %s", name, params)
+}
+
+// DOT はGraphvizのDOT形式で盤面を描く
+func DOT(tiles []*sugoroku.Tile) string {
+	var b strings.Builder
+	b.WriteString("digraph board {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"sans-serif\"];\n")
+
+	nodes := Nodes(tiles)
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "  t%d [label=%s, fillcolor=%q];\n", n.ID, dotQuote(label(n, "\n")), n.Color)
+	}
+	for _, n := range nodes {
+		for _, next := range n.Nexts {
+			fmt.Fprintf(&b, "  t%d -> t%d;\n", n.ID, next)
+		}
+		for _, prev := range n.Orphans {
+			fmt.Fprintf(&b, "  t%d -> t%d [style=dashed, label=\"prev\"];\n", prev, n.ID)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid はMermaidのflowchart形式で盤面を描く
+func Mermaid(tiles []*sugoroku.Tile) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	nodes := Nodes(tiles)
+	kinds := make(map[sugoroku.TileKind]string)
+	var kindOrder []sugoroku.TileKind
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "  t%d[\"%s\"]\n", n.ID, mermaidEscape(label(n, "<br/>")))
+		if _, seen := kinds[n.Kind]; !seen {
+			kinds[n.Kind] = n.Color
+			kindOrder = append(kindOrder, n.Kind)
+		}
+	}
+	for _, n := range nodes {
+		for _, next := range n.Nexts {
+			fmt.Fprintf(&b, "  t%d --> t%d\n", n.ID, next)
+		}
+		for _, prev := range n.Orphans {
+			fmt.Fprintf(&b, "  t%d -.->|prev| t%d\n", prev, n.ID)
+		}
+	}
+	for _, kind := range kindOrder {
+		fmt.Fprintf(&b, "  classDef %s fill:%s\n", mermaidClass(kind), kinds[kind])
+	}
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "  class t%d %s\n", n.ID, mermaidClass(n.Kind))
+	}
+	return b.String()
+}
+
+func label(n Node, sep string) string {
+	lines := []string{fmt.Sprintf("%d: %s", n.ID, n.Kind)}
+	if n.Effect != "" {
+		lines = append(lines, n.Effect)
+	}
+	if n.Detail != "" {
+		lines = append(lines, n.Detail)
+	}
+	return strings.Join(lines, sep)
+}
+
+func linksTo(from, to *sugoroku.Tile) bool {
+	for _, next := range from.Nexts() {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max]) + "…"
+}
+
+// dotQuote はDOTの文字列リテラルに変換する。改行は\nとして残す。
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// mermaidClass はclassDefに使える名前に変換する
+func mermaidClass(kind sugoroku.TileKind) string {
+	if kind == "" {
+		return "kind_none"
+	}
+	return "kind_" + string(kind)
+}
+
+// 対応している出力形式
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// ErrUnknownFormat は対応していない出力形式が指定された場合のエラー
+var ErrUnknownFormat = errors.New("unknown graph format")
+
+// Render は指定した形式で盤面を描く
+func Render(format string, tiles []*sugoroku.Tile) (string, error) {
+	switch format {
+	case FormatDOT:
+		return DOT(tiles), nil
+	case FormatMermaid:
+		return Mermaid(tiles), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,37 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTiles(t *testing.T) []*sugoroku.Tile {
+	t.Helper()
+	return sugoroku.NewGameWithTilesForTest("../../test/test_tiles.json").Tiles()
+}
+
+func TestDOT(t *testing.T) {
+	dot := DOT(testTiles(t))
+
+	assert.Contains(t, dot, "digraph board {")
+	assert.Contains(t, dot, `t2 [label="2: profit\nprofit {\"amount\":10}\n利益マス", fillcolor="#c8e6c9"];`)
+	assert.Contains(t, dot, "t4 -> t5;")
+	assert.Contains(t, dot, "t4 -> t6;")
+}
+
+func TestMermaid(t *testing.T) {
+	mermaid := Mermaid(testTiles(t))
+
+	assert.Contains(t, mermaid, "flowchart LR")
+	assert.Contains(t, mermaid, `t3["3: quiz<br/>quiz {#quot;quiz_id#quot;:1,#quot;amount#quot;:50}<br/>クイズマス"]`)
+	assert.Contains(t, mermaid, "t4 --> t6")
+	assert.Contains(t, mermaid, "classDef kind_branch fill:#fff59d")
+	assert.Contains(t, mermaid, "class t4 kind_branch")
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, err := Render("svg", testTiles(t))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,116 @@
+package boardstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// AddTile はマスを追加する。next_ids・prev_idsに書いたマスの側にも、このマスへのつながりを加える。
+func AddTile(tiles []sugoroku.TileJSON, tile sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+	if indexOf(tiles, tile.ID) >= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrTileExists, tile.ID)
+	}
+	tiles = append(tiles, tile)
+	link(tiles, tile)
+	return tiles, nil
+}
+
+// ReplaceTile はマスを置き換える。つながりが変わった場合は相手のマスの側も合わせて直す。
+func ReplaceTile(tiles []sugoroku.TileJSON, id int, tile sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+	i := indexOf(tiles, id)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrTileNotFound, id)
+	}
+	tile.ID = id
+	unlink(tiles, id)
+	tiles[i] = tile
+	link(tiles, tile)
+	return tiles, nil
+}
+
+// SetEffect はマスの効果だけを置き換える
+func SetEffect(tiles []sugoroku.TileJSON, id int, effect json.RawMessage) ([]sugoroku.TileJSON, error) {
+	i := indexOf(tiles, id)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrTileNotFound, id)
+	}
+	tiles[i].Effect = effect
+	return tiles, nil
+}
+
+// DeleteTile はマスを削除し、他のマスからのつながりも取り除く
+func DeleteTile(tiles []sugoroku.TileJSON, id int) ([]sugoroku.TileJSON, error) {
+	i := indexOf(tiles, id)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrTileNotFound, id)
+	}
+	unlink(tiles, id)
+	return append(tiles[:i], tiles[i+1:]...), nil
+}
+
+// link はtileのnext_ids・prev_idsに書かれたマスの側に、tileへのつながりを加える
+func link(tiles []sugoroku.TileJSON, tile sugoroku.TileJSON) {
+	for _, next := range tile.NextIDs {
+		if j := indexOf(tiles, next); j >= 0 && !contains(tiles[j].PrevIDs, tile.ID) {
+			tiles[j].PrevIDs = append(tiles[j].PrevIDs, tile.ID)
+		}
+	}
+	for _, prev := range tile.PrevIDs {
+		if j := indexOf(tiles, prev); j >= 0 && !contains(tiles[j].NextIDs, tile.ID) {
+			tiles[j].NextIDs = append(tiles[j].NextIDs, tile.ID)
+		}
+	}
+}
+
+// unlink は他のマスのnext_ids・prev_idsからidを取り除く
+func unlink(tiles []sugoroku.TileJSON, id int) {
+	for j := range tiles {
+		if tiles[j].ID == id {
+			continue
+		}
+		tiles[j].NextIDs = remove(tiles[j].NextIDs, id)
+		tiles[j].PrevIDs = remove(tiles[j].PrevIDs, id)
+	}
+}
+
+// copyTiles は版の盤面を書き換えないように、編集用に複製する
+func copyTiles(tiles []sugoroku.TileJSON) []sugoroku.TileJSON {
+	copied := make([]sugoroku.TileJSON, len(tiles))
+	for i, t := range tiles {
+		t.PrevIDs = append([]int{}, t.PrevIDs...)
+		t.NextIDs = append([]int{}, t.NextIDs...)
+		t.Effect = append(json.RawMessage(nil), t.Effect...)
+		copied[i] = t
+	}
+	return copied
+}
+
+func indexOf(tiles []sugoroku.TileJSON, id int) int {
+	for i, t := range tiles {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func contains(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(ids []int, id int) []int {
+	kept := ids[:0]
+	for _, v := range ids {
+		if v != id {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,45 @@
+package boardstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+func TestAddTile(t *testing.T) {
+	tiles, err := AddTile(initialTiles(t), sugoroku.TileJSON{ID: 4, Kind: "normal", PrevIDs: []int{1}, NextIDs: []int{3}})
+	assert.NoError(t, err)
+
+	// 相手のマスの側にもつながりが加わる
+	assert.Equal(t, []int{2, 4}, tiles[0].NextIDs)
+	assert.Equal(t, []int{2, 4}, tiles[2].PrevIDs)
+
+	_, err = AddTile(tiles, sugoroku.TileJSON{ID: 4})
+	assert.ErrorIs(t, err, ErrTileExists)
+}
+
+func TestReplaceTile(t *testing.T) {
+	tiles, err := AddTile(initialTiles(t), sugoroku.TileJSON{ID: 4, Kind: "normal", PrevIDs: []int{2}, NextIDs: []int{3}})
+	assert.NoError(t, err)
+
+	// マス2の先をマス4だけにつなぎ替える
+	tiles, err = ReplaceTile(tiles, 2, sugoroku.TileJSON{Kind: "normal", PrevIDs: []int{1}, NextIDs: []int{4}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tiles[1].ID)
+	assert.Equal(t, []int{4}, tiles[1].NextIDs)
+	assert.Equal(t, []int{4}, tiles[2].PrevIDs)
+	assert.Equal(t, []int{2}, tiles[3].PrevIDs)
+
+	_, err = ReplaceTile(tiles, 99, sugoroku.TileJSON{})
+	assert.ErrorIs(t, err, ErrTileNotFound)
+}
+
+func TestDeleteTile(t *testing.T) {
+	tiles, err := DeleteTile(initialTiles(t), 2)
+	assert.NoError(t, err)
+	assert.Len(t, tiles, 2)
+	assert.Empty(t, tiles[0].NextIDs)
+	assert.Empty(t, tiles[1].PrevIDs)
+}
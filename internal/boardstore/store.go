@@ -0,0 +1,249 @@
+package boardstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+var (
+	ErrVersionNotFound = errors.New("board version not found")
+	ErrTileNotFound    = errors.New("tile not found")
+	ErrTileExists      = errors.New("tile already exists")
+)
+
+// activeFile は新しいゲームで使う版の番号を保存するファイル
+const activeFile = "active.json"
+
+// Version は保存された盤面の1つの版
+type Version struct {
+	Number    int                 `json:"version"`
+	Parent    int                 `json:"parent,omitempty"` // 元にした版(最初の版は0)
+	CreatedAt time.Time           `json:"createdAt"`
+	Author    string              `json:"author,omitempty"`
+	Message   string              `json:"message,omitempty"`
+	Tiles     []sugoroku.TileJSON `json:"tiles"`
+}
+
+// VersionInfo は版の一覧で返す版の概要
+type VersionInfo struct {
+	Number    int       `json:"version"`
+	Parent    int       `json:"parent,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Author    string    `json:"author,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	TileCount int       `json:"tileCount"`
+	Active    bool      `json:"active"`
+}
+
+// Store は盤面の版をディレクトリに保存する。
+// 盤面を変更するたびに検証してから新しい版として保存し、過去の版は書き換えない。
+type Store struct {
+	dir      string
+	versions []*Version // 番号順
+	active   int        // 新しいゲームで使う版の番号
+
+	mu sync.RWMutex
+}
+
+// Open は保存先ディレクトリの版を読み込む。版が1つもない場合はinitialを最初の版として保存し、使う版にする。
+func Open(dir string, initial []sugoroku.TileJSON) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create board dir: %w", err)
+	}
+	s := &Store{dir: dir}
+
+	files, err := filepath.Glob(filepath.Join(dir, "v*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list board versions: %w", err)
+	}
+	for _, path := range files {
+		var v Version
+		if err := readJSON(path, &v); err != nil {
+			return nil, fmt.Errorf("failed to read board version %s: %w", path, err)
+		}
+		s.versions = append(s.versions, &v)
+	}
+	sort.Slice(s.versions, func(i, j int) bool { return s.versions[i].Number < s.versions[j].Number })
+
+	if len(s.versions) == 0 {
+		v, _, err := s.save(initial, sugoroku.LoadedQuizzes(), 0, "", "initial import")
+		if err != nil {
+			return nil, err
+		}
+		if err := s.setActive(v.Number); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	var active struct {
+		Version int `json:"version"`
+	}
+	if err := readJSON(filepath.Join(dir, activeFile), &active); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read active board version: %w", err)
+	}
+	if s.find(active.Version) == nil {
+		active.Version = s.versions[len(s.versions)-1].Number
+	}
+	s.active = active.Version
+	return s, nil
+}
+
+// List は保存されている版の概要を番号順に返す
+func (s *Store) List() []VersionInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	infos := make([]VersionInfo, 0, len(s.versions))
+	for _, v := range s.versions {
+		infos = append(infos, VersionInfo{
+			Number:    v.Number,
+			Parent:    v.Parent,
+			CreatedAt: v.CreatedAt,
+			Author:    v.Author,
+			Message:   v.Message,
+			TileCount: len(v.Tiles),
+			Active:    v.Number == s.active,
+		})
+	}
+	return infos
+}
+
+// Get は指定した番号の版を返す
+func (s *Store) Get(number int) (*Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v := s.find(number)
+	if v == nil {
+		return nil, fmt.Errorf("%w: %d", ErrVersionNotFound, number)
+	}
+	return v, nil
+}
+
+// Latest は最新の版を返す。盤面の編集は最新の版に対して行う。
+func (s *Store) Latest() *Version {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[len(s.versions)-1]
+}
+
+// Active は新しいゲームで使う版を返す
+func (s *Store) Active() *Version {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.find(s.active)
+}
+
+// Edit は最新の版の盤面にeditを適用し、検証してから新しい版として保存する。
+// 検証でエラーが見つかった場合は保存せずに*sugoroku.ValidationErrorを返す。
+func (s *Store) Edit(author, message string, edit func([]sugoroku.TileJSON) ([]sugoroku.TileJSON, error)) (*Version, []sugoroku.Issue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latest := s.versions[len(s.versions)-1]
+	tiles, err := edit(copyTiles(latest.Tiles))
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.save(tiles, sugoroku.LoadedQuizzes(), latest.Number, author, message)
+}
+
+// Import はファイルから読み込んだ盤面を、quizzesと組み合わせて検証してから新しい版として保存する。
+// クイズも差し替える盤面の読み直しで、差し替え後のクイズで検証するために使う。
+func (s *Store) Import(author, message string, tiles []sugoroku.TileJSON, quizzes []sugoroku.Quiz) (*Version, []sugoroku.Issue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(copyTiles(tiles), quizzes, s.versions[len(s.versions)-1].Number, author, message)
+}
+
+// Rollback は指定した版の盤面を最新の版として保存し直す。過去の版はそのまま残る。
+func (s *Store) Rollback(number int, author string) (*Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := s.find(number)
+	if v == nil {
+		return nil, fmt.Errorf("%w: %d", ErrVersionNotFound, number)
+	}
+	saved, _, err := s.save(copyTiles(v.Tiles), sugoroku.LoadedQuizzes(), number, author, fmt.Sprintf("rollback to version %d", number))
+	return saved, err
+}
+
+// SetActive は新しいゲームで使う版を変更する。進行中のゲームの盤面は変わらない。
+func (s *Store) SetActive(number int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.find(number) == nil {
+		return fmt.Errorf("%w: %d", ErrVersionNotFound, number)
+	}
+	return s.setActive(number)
+}
+
+// save は盤面をクイズと組み合わせて検証してから次の番号の版として保存する
+func (s *Store) save(tiles []sugoroku.TileJSON, quizzes []sugoroku.Quiz, parent int, author, message string) (*Version, []sugoroku.Issue, error) {
+	issues := sugoroku.ValidateTiles(tiles, quizzes)
+	if errs := sugoroku.ErrorIssues(issues); len(errs) > 0 {
+		return nil, issues, &sugoroku.ValidationError{Issues: errs}
+	}
+
+	number := 1
+	if n := len(s.versions); n > 0 {
+		number = s.versions[n-1].Number + 1
+	}
+	v := &Version{
+		Number:    number,
+		Parent:    parent,
+		CreatedAt: time.Now(),
+		Author:    author,
+		Message:   message,
+		Tiles:     tiles,
+	}
+	if err := writeJSON(filepath.Join(s.dir, fmt.Sprintf("v%04d.json", number)), v); err != nil {
+		return nil, issues, fmt.Errorf("failed to save board version %d: %w", number, err)
+	}
+	s.versions = append(s.versions, v)
+	return v, issues, nil
+}
+
+func (s *Store) setActive(number int) error {
+	if err := writeJSON(filepath.Join(s.dir, activeFile), map[string]int{"version": number}); err != nil {
+		return fmt.Errorf("failed to save active board version: %w", err)
+	}
+	s.active = number
+	return nil
+}
+
+func (s *Store) find(number int) *Version {
+	for _, v := range s.versions {
+		if v.Number == number {
+			return v
+		}
+	}
+	return nil
+}
+
+// writeJSON は一時ファイルに書いてから置き換え、書き込み途中のファイルが残らないようにする
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,102 @@
+package boardstore
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+const initialBoardJSON = `[
+	{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+	{"id": 2, "kind": "profit", "effect": {"type": "profit", "amount": 10}, "prev_ids": [1], "next_ids": [3]},
+	{"id": 3, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+]`
+
+func initialTiles(t *testing.T) []sugoroku.TileJSON {
+	t.Helper()
+	var tiles []sugoroku.TileJSON
+	assert.NoError(t, json.Unmarshal([]byte(initialBoardJSON), &tiles))
+	return tiles
+}
+
+func TestOpen_ImportsInitialVersion(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, initialTiles(t))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, s.Latest().Number)
+	assert.Equal(t, 1, s.Active().Number)
+	assert.Len(t, s.List(), 1)
+	assert.True(t, s.List()[0].Active)
+
+	// 開き直しても版と使う版が引き継がれる
+	_, _, err = s.Edit("admin", "profit 20", func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return SetEffect(tiles, 2, json.RawMessage(`{"type": "profit", "amount": 20}`))
+	})
+	assert.NoError(t, err)
+	reopened, err := Open(dir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reopened.Latest().Number)
+	assert.Equal(t, 1, reopened.Active().Number)
+	assert.Equal(t, "profit 20", reopened.Latest().Message)
+}
+
+func TestStore_Edit(t *testing.T) {
+	s, err := Open(t.TempDir(), initialTiles(t))
+	assert.NoError(t, err)
+
+	v, _, err := s.Edit("admin", "", func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return SetEffect(tiles, 2, json.RawMessage(`{"type": "loss", "amount": 5}`))
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v.Number)
+	assert.Equal(t, 1, v.Parent)
+	assert.Equal(t, "admin", v.Author)
+
+	// 過去の版は書き換わらない
+	first, err := s.Get(1)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "profit", "amount": 10}`, string(first.Tiles[1].Effect))
+
+	// 検証でエラーになる変更は保存されない
+	_, _, err = s.Edit("admin", "", func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return SetEffect(tiles, 2, json.RawMessage(`{"type": "unknown"}`))
+	})
+	var validationErr *sugoroku.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, 2, s.Latest().Number)
+
+	// 編集関数のエラーはそのまま返す
+	_, _, err = s.Edit("admin", "", func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return DeleteTile(tiles, 99)
+	})
+	assert.ErrorIs(t, err, ErrTileNotFound)
+}
+
+func TestStore_RollbackAndSetActive(t *testing.T) {
+	s, err := Open(t.TempDir(), initialTiles(t))
+	assert.NoError(t, err)
+	_, _, err = s.Edit("admin", "", func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return SetEffect(tiles, 2, json.RawMessage(`{"type": "loss", "amount": 5}`))
+	})
+	assert.NoError(t, err)
+
+	v, err := s.Rollback(1, "admin")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v.Number)
+	assert.Equal(t, 1, v.Parent)
+	assert.JSONEq(t, `{"type": "profit", "amount": 10}`, string(v.Tiles[1].Effect))
+	// 巻き戻しても使う版は変わらない
+	assert.Equal(t, 1, s.Active().Number)
+
+	assert.NoError(t, s.SetActive(2))
+	assert.Equal(t, 2, s.Active().Number)
+
+	_, err = s.Rollback(99, "admin")
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	assert.ErrorIs(t, s.SetActive(99), ErrVersionNotFound)
+}
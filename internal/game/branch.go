@@ -0,0 +1,76 @@
+package game
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// pendingBranch は分岐マスで進む道を選ぶ入力要求
+const pendingBranch PendingKind = "branch"
+
+func init() {
+	RegisterInteraction(sugoroku.BranchEffect{}, Interaction{
+		Kind:    pendingBranch,
+		Command: CommandSubmitChoice,
+		Begin: func(gm *GameManager, player *sugoroku.Player, p *Pending) (bool, error) {
+			// 残りの歩数は道を選んだ後に進む
+			p.steps = player.TakeRemainingSteps()
+			return true, nil
+		},
+		Prompt: func(gm *GameManager, player *sugoroku.Player, p *Pending) (map[string]any, error) {
+			return gm.branchSelectionEvent(player.Position), nil
+		},
+		Submit: func(gm *GameManager, player *sugoroku.Player, p *Pending, payload map[string]any) error {
+			return gm.chooseBranch(player, p, payload["selection"])
+		},
+		// 期限切れの場合は最初の道を選ぶ
+		Default: func(gm *GameManager, player *sugoroku.Player, p *Pending) (any, error) {
+			tile := player.Position
+			options, _ := tile.Effect.GetOptions(tile, gm.game).([]int)
+			if len(options) == 0 {
+				return nil, fmt.Errorf("branch tile %d has no options", tile.Id)
+			}
+			return options[0], nil
+		},
+		Expire: func(gm *GameManager, player *sugoroku.Player, p *Pending, selection any) error {
+			return gm.chooseBranch(player, p, selection)
+		},
+	})
+}
+
+// SUBMIT_CHOICEリクエスト時に発火する関数。
+// 選んだタイルIDの方向へ移動させる。
+func (gm *GameManager) HandleBranch(playerID string, choiceData map[string]interface{}) error {
+	return gm.HandleSubmit(CommandSubmitChoice, playerID, choiceData)
+}
+
+// chooseBranch は分岐で選んだマスへ進み、サイコロの残りの歩数も進む。止まったマスの効果は回答の後に処理される
+func (gm *GameManager) chooseBranch(player *sugoroku.Player, p *Pending, choice any) error {
+	effect := player.Position.Effect
+	if err := effect.Apply(player, gm.game, choice); err != nil {
+		return fmt.Errorf("failed to apply choice: %w", err)
+	}
+
+	// 選んだマスへの1歩を除いた、サイコロの残りの歩数だけ進む
+	if p.steps > 1 {
+		player.MoveOn(p.steps - 1)
+	}
+	log.WithFields(log.Fields{
+		"playerID":    player.Id,
+		"newPosition": player.Position.Id,
+	}).Info("Player moved by branch choice")
+	return nil
+}
+
+func (gm *GameManager) branchSelectionEvent(tile *sugoroku.Tile) map[string]any {
+	return map[string]any{
+		"type": "BRANCH_CHOICE_REQUIRED",
+		"payload": map[string]any{
+			"tileID":  tile.Id,
+			"options": tile.Effect.GetOptions(tile, gm.game),
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// pendingChoice は選択マスで効果を選ぶ入力要求
+const pendingChoice PendingKind = "choice"
+
+func init() {
+	RegisterInteraction(sugoroku.ChoiceEffect{}, Interaction{
+		Kind:    pendingChoice,
+		Command: CommandSubmitEffectChoice,
+		Prompt: func(gm *GameManager, player *sugoroku.Player, p *Pending) (map[string]any, error) {
+			return gm.effectChoiceEvent(player.Position), nil
+		},
+		Submit: func(gm *GameManager, player *sugoroku.Player, p *Pending, payload map[string]any) error {
+			return gm.chooseEffect(player, p, payload["selection"])
+		},
+		// 期限切れの場合は最初の選択肢を選ぶ
+		Default: func(gm *GameManager, player *sugoroku.Player, p *Pending) (any, error) {
+			return 0, nil
+		},
+		Expire: func(gm *GameManager, player *sugoroku.Player, p *Pending, selection any) error {
+			return gm.chooseEffect(player, p, selection)
+		},
+	})
+}
+
+// SUBMIT_EFFECT_CHOICEリクエスト時に発火する関数。
+// 選択マスで選んだ選択肢の効果を適用する。
+func (gm *GameManager) HandleEffectChoice(playerID string, choiceData map[string]interface{}) error {
+	return gm.HandleSubmit(CommandSubmitEffectChoice, playerID, choiceData)
+}
+
+// chooseEffect は選択マスで選んだ選択肢の効果を適用する。
+// 選んだ効果で別のマスへ移動した場合は、回答の後に移動先のマスの効果も処理される。
+func (gm *GameManager) chooseEffect(player *sugoroku.Player, p *Pending, selection any) error {
+	tile := player.Position
+	choiceEffect, ok := tile.Effect.(sugoroku.ChoiceEffect)
+	if !ok {
+		return fmt.Errorf("tile %d is not a choice tile", tile.Id)
+	}
+	selected, err := choiceEffect.Selected(selection)
+	if err != nil {
+		return fmt.Errorf("failed to apply choice: %w", err)
+	}
+	if selected == nil {
+		return nil
+	}
+	if err := gm.applyEffect(player, selected, nil, p.reporter); err != nil {
+		return fmt.Errorf("failed to apply choice: %w", err)
+	}
+	return nil
+}
+
+func (gm *GameManager) effectChoiceEvent(tile *sugoroku.Tile) map[string]any {
+	return map[string]any{
+		"type": "EFFECT_CHOICE_REQUIRED",
+		"payload": map[string]any{
+			"tileID":  tile.Id,
+			"options": tile.Effect.GetOptions(tile, gm.game),
+		},
+	}
+}
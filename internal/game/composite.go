@@ -0,0 +1,50 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+func init() {
+	// 組み合わせの効果は、中の効果を1つ適用するごとに変化を通知する
+	registerEffectHandler(sugoroku.SequenceEffect{}, effectHandler{
+		apply: func(gm *GameManager, player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) error {
+			for i, step := range effect.(sugoroku.SequenceEffect).Steps() {
+				if err := gm.applyEffect(player, step, nil, r); err != nil {
+					return fmt.Errorf("effects[%d]: %w", i, err)
+				}
+			}
+			return nil
+		},
+	})
+	// 条件分岐は選ばれた方の効果を適用する。中にくじ引きなどがあっても通知されるようにする
+	registerEffectHandler(sugoroku.ConditionalEffect{}, effectHandler{
+		apply: func(gm *GameManager, player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) error {
+			branch, err := effect.(sugoroku.ConditionalEffect).Branch(player, gm.game)
+			if err != nil {
+				return err
+			}
+			if branch == nil {
+				return nil
+			}
+			return gm.applyEffect(player, branch, nil, r)
+		},
+	})
+	// くじ引きは抽選結果を先に通知してから、結果の効果を適用する
+	registerEffectHandler(sugoroku.LotteryEffect{}, effectHandler{
+		apply: func(gm *GameManager, player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) error {
+			lottery := effect.(sugoroku.LotteryEffect)
+			index, err := lottery.Draw(gm.game)
+			if err != nil {
+				return err
+			}
+			outcome, label := lottery.Outcome(index)
+			gm.broadcastLotteryResult(player.Id, player.Position.Id, index, label)
+			if outcome == nil {
+				return nil
+			}
+			return gm.applyEffect(player, outcome, nil, r)
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// effectHandler は入力は要らないが、止まったときや適用するときにGameManager側の処理が必要な効果の扱い方。
+// 効果ごとのファイルのinitでregisterEffectHandlerを呼んで登録する。
+type effectHandler struct {
+	// land は止まったときの処理をする。省略すると効果を適用する
+	land func(gm *GameManager, player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) (landingResult, error)
+	// apply は効果を適用して変化を通知する。組み合わせの効果の中の効果として適用するときにも使う。省略するとApplyを呼ぶ
+	apply func(gm *GameManager, player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) error
+}
+
+var effectHandlers = make(map[reflect.Type]*effectHandler)
+
+// registerEffectHandler は効果の扱い方を登録する。effectには効果の型のゼロ値を渡す。
+func registerEffectHandler(effect sugoroku.EffectType, h effectHandler) {
+	t := reflect.TypeOf(effect)
+	if _, dup := effectHandlers[t]; dup {
+		panic(fmt.Sprintf("effect handler for %v is already registered", t))
+	}
+	effectHandlers[t] = &h
+}
+
+// effectHandlerFor は効果に登録された扱い方を返す
+func effectHandlerFor(effect sugoroku.EffectType) (*effectHandler, bool) {
+	h, ok := effectHandlers[reflect.TypeOf(effect)]
+	return h, ok
+}
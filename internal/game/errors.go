@@ -0,0 +1,18 @@
+package game
+
+// GameError はクライアントにそのまま返せる、コード付きのゲーム進行エラー
+type GameError struct {
+	Code    string
+	Message string
+}
+
+func (e *GameError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+var (
+	ErrNotYourTurn      = &GameError{Code: "not_your_turn", Message: "あなたの手番ではありません"}
+	ErrPendingAction    = &GameError{Code: "pending_action", Message: "先にマスの選択に回答してください"}
+	ErrUnexpectedSubmit = &GameError{Code: "unexpected_submit", Message: "現在その回答は受け付けていません"}
+	ErrRoomClosed       = &GameError{Code: "room_closed", Message: "部屋は閉じられました"}
+)
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// pendingGamble はギャンブルマスで賭けを決める入力要求
+const pendingGamble PendingKind = "gamble"
+
+func init() {
+	RegisterInteraction(sugoroku.GambleEffect{}, Interaction{
+		Kind:    pendingGamble,
+		Command: CommandSubmitGamble,
+		// 最低賭け金を払えない場合は賭けずに通り過ぎる
+		Begin: func(gm *GameManager, player *sugoroku.Player, p *Pending) (bool, error) {
+			effect := player.Position.Effect.(sugoroku.GambleEffect)
+			if minBet, maxBet := effect.BetRange(player); maxBet < minBet {
+				log.WithFields(log.Fields{
+					"playerID": player.Id,
+					"tileID":   player.Position.Id,
+					"money":    player.Money,
+				}).Info("Player cannot afford the minimum bet, skipping gamble")
+				return false, nil
+			}
+			return true, nil
+		},
+		Prompt: func(gm *GameManager, player *sugoroku.Player, p *Pending) (map[string]any, error) {
+			return gm.gambleRequireEvent(player, player.Position, player.Position.Effect.(sugoroku.GambleEffect)), nil
+		},
+		Submit: func(gm *GameManager, player *sugoroku.Player, p *Pending, payload map[string]any) error {
+			return gm.playGamble(player, payload)
+		},
+		// 期限切れの場合は賭けなしとして扱うので、Expireは登録しない
+	})
+}
+
+// SUBMIT_GAMBLEリクエスト時に発火する関数。
+// ペイロードから賭け金と選択を読み込み、マスに設定されたゲームでギャンブルを行う。
+// Gambleの結果をプレイヤーに返す。
+func (gm *GameManager) HandleGamble(playerID string, payload map[string]interface{}) error {
+	return gm.HandleSubmit(CommandSubmitGamble, playerID, payload)
+}
+
+// playGamble は賭けの内容を読み取ってギャンブルを行い、結果をプレイヤーに送る
+func (gm *GameManager) playGamble(player *sugoroku.Player, payload map[string]any) error {
+	tile := player.Position
+	effect, ok := tile.Effect.(sugoroku.GambleEffect)
+	if !ok {
+		return fmt.Errorf("tile %d is not a gamble tile", tile.Id)
+	}
+	input, err := sugoroku.ParseGambleInput(payload)
+	if err != nil {
+		return fmt.Errorf("failed to apply gamble choice: %w", err)
+	}
+
+	result, err := effect.Play(player, gm.game, input)
+	if err != nil {
+		return fmt.Errorf("failed to apply gamble choice: %w", err)
+	}
+	gm.sendGambleResult(player.Id, result)
+	return nil
+}
+
+func (gm *GameManager) gambleRequireEvent(player *sugoroku.Player, tile *sugoroku.Tile, effect sugoroku.GambleEffect) map[string]any {
+	prompt := effect.Prompt(player, gm.game)
+	payload := map[string]any{
+		"tileID": tile.Id,
+		"game":   prompt.Game,
+		"payout": prompt.Payout,
+		"minBet": prompt.MinBet,
+		"maxBet": prompt.MaxBet,
+	}
+	if prompt.Game == sugoroku.GambleHighLow {
+		payload["referenceValue"] = prompt.ReferenceValue
+	}
+	if prompt.Options != nil {
+		payload["options"] = prompt.Options
+	}
+	return map[string]any{
+		"type":    "GAMBLE_REQUIRED",
+		"payload": payload,
+	}
+}
+
+func (gm *GameManager) sendGambleResult(playerID string, result sugoroku.GambleResult) {
+	payload := map[string]any{
+		"userID":   playerID,
+		"game":     result.Game,
+		"bet":      result.Bet,
+		"won":      result.Won,
+		"amount":   result.Amount,
+		"newMoney": result.NewMoney,
+	}
+	if result.Choice != nil {
+		payload["choice"] = result.Choice
+	}
+	if result.Game != sugoroku.GambleDoubleOrNothing {
+		payload["diceResult"] = result.DiceResult
+	}
+	event := map[string]any{
+		"type":    "GAMBLE_RESULT",
+		"payload": payload,
+	}
+	if err := gm.sendToPlayer(playerID, event); err != nil {
+		log.WithFields(log.Fields{
+			"error":    err,
+			"playerID": playerID,
+		}).Error("failed to send gamble result to player")
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+func init() {
+	registerEffectHandler(sugoroku.RequireEffect{}, effectHandler{
+		land: func(gm *GameManager, player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) (landingResult, error) {
+			passed, err := gm.checkGate(player, effect.(sugoroku.RequireEffect))
+			if err != nil {
+				return landingDone, err
+			}
+			r.report()
+			// 関所を通過したら、関所で止まったときの残りの歩数だけ進む
+			steps := player.TakeRemainingSteps()
+			if !passed || steps == 0 {
+				return landingDone, nil
+			}
+			player.Move(steps)
+			r.report()
+			return landingDone, nil
+		},
+	})
+}
+
+// passHeldGate は関所に足止めされているプレイヤーに、もう一度関所の判定をさせる。
+// 足止めされていない場合と、関所を通過できた場合はtrueを返す。
+func (gm *GameManager) passHeldGate(player *sugoroku.Player) (bool, error) {
+	if !player.IsHeld() {
+		return true, nil
+	}
+	gate, ok := player.Position.Effect.(sugoroku.RequireEffect)
+	if !ok {
+		return true, nil
+	}
+	return gm.checkGate(player, gate)
+}
+
+// checkGate は関所の判定を行い、結果を全クライアントに通知する
+func (gm *GameManager) checkGate(player *sugoroku.Player, gate sugoroku.RequireEffect) (bool, error) {
+	result, err := gate.Check(player, player.LastRoll())
+	if err != nil {
+		return false, fmt.Errorf("failed to check gate: %w", err)
+	}
+	gm.broadcastGateResult(player.Id, player.Position.Id, result)
+	log.WithFields(log.Fields{
+		"playerID": player.Id,
+		"tileID":   player.Position.Id,
+		"passed":   result.Passed,
+	}).Info("Gate checked")
+	return result.Passed, nil
+}
@@ -0,0 +1,14 @@
+package game
+
+import (
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+func init() {
+	registerEffectHandler(sugoroku.GoalEffect{}, effectHandler{
+		// TODO: ゴールした際に行う処理(clientとの接続解除など)を行ったほうが良いと思う
+		land: func(gm *GameManager, player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) (landingResult, error) {
+			return landingGoal, gm.Goal(player.Id, gm.playerClients[player.Id])
+		},
+	})
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// Interaction はプレイヤーの入力が必要な効果の扱い方。
+// 効果を定義したファイルのinitでRegisterInteractionを呼んで登録すると、止まったときの入力要求、
+// 回答コマンドの受け付け、再接続したときの再送、回答期限切れの処理がこの定義で行われる。
+// 回答を適用した後の入力待ちの解除、変化の通知、移動先のマスの処理、手番の終了はGameManagerが行う。
+type Interaction struct {
+	Kind    PendingKind // 入力要求の種類
+	Command string      // 回答として受け付けるコマンド
+
+	// Begin は入力を求める前の準備をする。falseを返すと入力を求めずに通り過ぎる。省略できる。
+	Begin func(gm *GameManager, player *sugoroku.Player, p *Pending) (bool, error)
+	// Prompt は入力要求のイベントを作る。再接続したプレイヤーへの再送にも使う。
+	Prompt func(gm *GameManager, player *sugoroku.Player, p *Pending) (map[string]any, error)
+	// Submit はプレイヤーの回答を適用する。エラーを返した場合は入力待ちのまま残る。
+	Submit func(gm *GameManager, player *sugoroku.Player, p *Pending, payload map[string]any) error
+	// Default は回答期限が過ぎたときに代わりに選ぶ選択肢を返す。DECISION_TIMEOUTで通知する。省略するとnil。
+	Default func(gm *GameManager, player *sugoroku.Player, p *Pending) (any, error)
+	// Expire は回答期限が過ぎたときに既定の回答を適用する。省略すると何も適用せずに進む。
+	Expire func(gm *GameManager, player *sugoroku.Player, p *Pending, selection any) error
+}
+
+var (
+	interactionsByEffect  = make(map[reflect.Type]*Interaction)
+	interactionsByKind    = make(map[PendingKind]*Interaction)
+	interactionsByCommand = make(map[string]*Interaction)
+)
+
+// RegisterInteraction は入力が必要な効果の扱い方を登録する。effectには効果の型のゼロ値を渡す。
+// 効果と同じファイルのinitで呼ぶ想定で、同じ効果やコマンドを二重に登録するとpanicする。
+func RegisterInteraction(effect sugoroku.EffectType, in Interaction) {
+	t := reflect.TypeOf(effect)
+	if _, dup := interactionsByEffect[t]; dup {
+		panic(fmt.Sprintf("interaction for %v is already registered", t))
+	}
+	if _, dup := interactionsByCommand[in.Command]; dup {
+		panic(fmt.Sprintf("command %s is already registered", in.Command))
+	}
+	if in.Prompt == nil || in.Submit == nil {
+		panic(fmt.Sprintf("interaction for %v needs Prompt and Submit", t))
+	}
+	interactionsByEffect[t] = &in
+	interactionsByKind[in.Kind] = &in
+	interactionsByCommand[in.Command] = &in
+}
+
+// interactionFor は効果に登録された入力の扱い方を返す
+func interactionFor(effect sugoroku.EffectType) (*Interaction, bool) {
+	in, ok := interactionsByEffect[reflect.TypeOf(effect)]
+	return in, ok
+}
+
+// IsSubmitCommand は入力要求への回答として登録されたコマンドかどうかを返す
+func IsSubmitCommand(command string) bool {
+	_, ok := interactionsByCommand[command]
+	return ok
+}
+
+// requestInput は止まったマスの入力要求を記録し、プレイヤーに入力を求める
+func (gm *GameManager) requestInput(player *sugoroku.Player, in *Interaction) (landingResult, error) {
+	p := gm.setPending(player.Id, in.Kind, player.Position.Id)
+	if in.Begin != nil {
+		ok, err := in.Begin(gm, player, p)
+		if err != nil || !ok {
+			gm.clearPending(player.Id)
+			return landingDone, err
+		}
+	}
+	event, err := in.Prompt(gm, player, p)
+	if err != nil {
+		return landingPending, err
+	}
+	return landingPending, gm.sendPrompt(player.Id, event)
+}
+
+// HandleSubmit は入力要求への回答コマンド(SUBMIT_*)を受け付け、登録された効果の処理に渡す
+func (gm *GameManager) HandleSubmit(command, playerID string, payload map[string]any) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if err := gm.checkOpen(); err != nil {
+		return err
+	}
+	gm.record(journal.KindCommand, command, playerID, payload)
+	in, ok := interactionsByCommand[command]
+	if !ok {
+		return fmt.Errorf("unknown submit command %s", command)
+	}
+	player, err := gm.game.GetPlayer(playerID)
+	if err != nil {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	if err := gm.checkPending(playerID, in.Kind); err != nil {
+		return err
+	}
+	return gm.resolvePending(player, func(p *Pending) error {
+		return in.Submit(gm, player, p, payload)
+	})
+}
+
+// resolvePending は入力要求への回答をapplyで適用し、入力待ちを解除する。
+// 回答で別のマスへ移動していれば移動先のマスの効果を処理し、そうでなければ変化を通知して手番を終える。
+func (gm *GameManager) resolvePending(player *sugoroku.Player, apply func(p *Pending) error) error {
+	p := gm.pending[player.Id]
+	from := player.Position
+	r := gm.newStatusReporter(player)
+	p.reporter = r
+	if err := apply(p); err != nil {
+		return err
+	}
+	gm.clearPending(player.Id)
+	return gm.finishAction(player, from, r)
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// ジャーナルに記録するコマンドの種類
+const (
+	CommandGameStarted        = "GAME_STARTED"
+	CommandPlayerJoined       = "PLAYER_JOINED"
+	CommandPlayerLeft         = "PLAYER_LEFT"
+	CommandRollDice           = "ROLL_DICE"
+	CommandSubmitChoice       = "SUBMIT_CHOICE"
+	CommandSubmitGamble       = "SUBMIT_GAMBLE"
+	CommandSubmitQuiz         = "SUBMIT_QUIZ"
+	CommandSubmitEffectChoice = "SUBMIT_EFFECT_CHOICE"
+	CommandDecisionTimeout    = "DECISION_TIMEOUT"
+	CommandRestored           = "RESTORED"
+	CommandBoardReloaded      = "BOARD_RELOADED"
+)
+
+// gameStartedPayload はゲームを再現するために必要な初期設定
+type gameStartedPayload struct {
+	Seed      int64               `json:"seed"`
+	Dice      sugoroku.DiceConfig `json:"dice"`
+	TurnBased bool                `json:"turnBased"`
+}
+
+// SetJournal はコマンドとイベントの記録先を設定し、ゲームの初期設定を記録する
+func (gm *GameManager) SetJournal(j journal.Journal) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	gm.journal = j
+	gm.record(journal.KindCommand, CommandGameStarted, "", gameStartedPayload{
+		Seed:      gm.game.Seed(),
+		Dice:      gm.game.DiceConfig(),
+		TurnBased: gm.turnBased,
+	})
+}
+
+// record はジャーナルが設定されていれば1件追記する。記録の失敗でゲームは止めない。
+func (gm *GameManager) record(kind journal.Kind, entryType string, playerID string, payload any) {
+	if gm.journal == nil {
+		return
+	}
+	if err := gm.journal.Append(kind, entryType, playerID, payload); err != nil {
+		log.WithError(err).WithField("type", entryType).Error("failed to append journal entry")
+	}
+}
+
+// Replay はジャーナルに記録されたコマンドを先頭から順に適用し、ゲームの状態を再構築する。
+// gには記録時と同じ盤面を読み込んだゲームを渡す。シードとサイコロはジャーナルの値で上書きされる。
+func Replay(g *sugoroku.Game, entries []journal.Entry) (*GameManager, error) {
+	gm := NewHeadlessGameManager(g)
+
+	for _, entry := range entries {
+		if entry.Kind != journal.KindCommand {
+			continue
+		}
+
+		var payload map[string]any
+		if len(entry.Payload) > 0 {
+			if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+				return nil, fmt.Errorf("invalid payload at seq %d: %w", entry.Seq, err)
+			}
+		}
+
+		if err := gm.replayCommand(entry, payload); err != nil {
+			// 記録時にエラーになった操作は再現時も同じくエラーになるので、そのまま続ける
+			log.WithError(err).WithFields(log.Fields{
+				"seq":  entry.Seq,
+				"type": entry.Type,
+			}).Info("Replayed command returned error")
+		}
+	}
+	return gm, nil
+}
+
+func (gm *GameManager) replayCommand(entry journal.Entry, payload map[string]any) error {
+	switch entry.Type {
+	case CommandGameStarted:
+		var started gameStartedPayload
+		if err := json.Unmarshal(entry.Payload, &started); err != nil {
+			return err
+		}
+		gm.game.SetSeed(started.Seed)
+		if err := gm.game.ConfigureDice(started.Dice); err != nil {
+			return err
+		}
+		gm.SetTurnBased(started.TurnBased)
+		return nil
+	case CommandRestored:
+		var snapshot Snapshot
+		if err := json.Unmarshal(entry.Payload, &snapshot); err != nil {
+			return err
+		}
+		return gm.Restore(snapshot)
+	case CommandBoardReloaded:
+		var board sugoroku.Board
+		if err := json.Unmarshal(entry.Payload, &board); err != nil {
+			return err
+		}
+		// 共通のクイズは動いている部屋も使うので、再生しているゲームだけ記録時のクイズに差し替える
+		gm.game.SetQuizzes(board.Quizzes)
+		return gm.ReloadBoard(&board)
+	case CommandPlayerJoined:
+		return gm.RegisterPlayerClient(entry.PlayerID, nil)
+	case CommandPlayerLeft:
+		return gm.UnregisterPlayerClient(entry.PlayerID, nil)
+	case CommandRollDice:
+		return gm.HandleMove(entry.PlayerID)
+	case CommandDecisionTimeout:
+		return gm.ExpireDecision(entry.PlayerID)
+	default:
+		if IsSubmitCommand(entry.Type) {
+			return gm.HandleSubmit(entry.Type, entry.PlayerID, payload)
+		}
+		return fmt.Errorf("unknown command %s", entry.Type)
+	}
+}
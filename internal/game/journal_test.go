@@ -0,0 +1,93 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameManager_JournalReplay(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	gm.game.SetSeed(42)
+
+	mem := journal.NewMemory()
+	gm.SetJournal(mem)
+
+	players := []string{"player1", "player2"}
+	for _, id := range players {
+		createAndRegisterClient(t, gm, h, id)
+	}
+
+	// 入力を求められたら回答しながら、ゴールまたは終点に着くまで進める
+	for round := 0; round < 5; round++ {
+		for _, id := range players {
+			if _, err := gm.game.GetPlayer(id); err != nil {
+				continue
+			}
+			assert.NoError(t, gm.HandleMove(id))
+
+			p, ok := gm.pending[id]
+			if !ok {
+				continue
+			}
+			switch p.kind {
+			case pendingBranch:
+				assert.NoError(t, gm.HandleBranch(id, map[string]any{"selection": float64(6)}))
+			case pendingQuiz:
+				assert.NoError(t, gm.HandleQuiz(id, map[string]any{"quizID": float64(1), "selection": float64(1)}))
+			}
+		}
+	}
+
+	// コマンドとイベントの両方が記録されている
+	entries := mem.Entries()
+	assert.Equal(t, CommandGameStarted, entries[0].Type)
+	var hasEvent bool
+	for _, e := range entries {
+		if e.Kind == journal.KindEvent && e.Type == "PLAYER_MOVED" {
+			hasEvent = true
+		}
+	}
+	assert.True(t, hasEvent)
+
+	// 別のゲームで再生すると同じ状態になる
+	replayed := sugoroku.NewGameWithTilesForTest(tilePath)
+	_, err := Replay(replayed, entries)
+	assert.NoError(t, err)
+
+	for _, id := range players {
+		want, err := gm.game.GetPlayer(id)
+		assert.NoError(t, err)
+		got, err := replayed.GetPlayer(id)
+		assert.NoError(t, err)
+		assert.Equal(t, want.Position.Id, got.Position.Id)
+		assert.Equal(t, want.Money, got.Money)
+	}
+	assert.Equal(t, gm.game.Seed(), replayed.Seed())
+}
+
+func TestReplay_BoardReloadKeepsSharedQuizzes(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	quizPath := getTestFilePath(t, "test/test_quizzes.json")
+	gm, _ := setupTestEnvironment(t, tilePath)
+	mem := journal.NewMemory()
+	gm.SetJournal(mem)
+
+	board, _, err := sugoroku.LoadBoard(tilePath, quizPath)
+	assert.NoError(t, err)
+	board.Quizzes = []sugoroku.Quiz{{ID: 99, Question: "journaled", Options: []string{"a", "b"}}}
+	assert.NoError(t, gm.ReloadBoard(board))
+
+	// 再生しても動いている部屋が使う共通のクイズは変わらず、再生したゲームだけが記録時のクイズを使う
+	shared := sugoroku.LoadedQuizzes()
+	replayed := sugoroku.NewGameWithTilesForTest(tilePath)
+	_, err = Replay(replayed, mem.Entries())
+	assert.NoError(t, err)
+	assert.Equal(t, shared, sugoroku.LoadedQuizzes())
+	assert.Equal(t, board.Quizzes, replayed.Quizzes())
+	_, ok := replayed.FindQuiz(99)
+	assert.True(t, ok)
+}
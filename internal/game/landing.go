@@ -0,0 +1,107 @@
+package game
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// maxLandingChain は移動マスから移動マスへ続けて移れる回数の上限。
+// 移動マス同士が互いを指しているような盤面で無限に移動し続けないようにする。
+const maxLandingChain = 16
+
+// landingResult は止まったマスの効果を処理した結果
+type landingResult int
+
+const (
+	landingDone    landingResult = iota // 効果の処理が終わった。手番を終えてよい
+	landingPending                      // プレイヤーの入力を待っている
+	landingGoal                         // ゴールした
+)
+
+// settleMove は移動を終えたプレイヤーについて、移動の通知、止まったマスの効果の処理、変化の通知、手番の終了までを行う。
+// サイコロ、分岐の選択、マスの効果など、どの経路で移動した場合もここを通す。
+func (gm *GameManager) settleMove(player *sugoroku.Player, r *statusReporter) error {
+	r.report()
+	result, err := gm.resolveLanding(player, r)
+	if err != nil {
+		return err
+	}
+	if result == landingGoal {
+		return nil //ゲーム終了するので通知も手番の終了もしない
+	}
+	r.report()
+
+	// 入力待ちの場合は、回答を受け取ってから手番を終える
+	if result == landingDone {
+		gm.endTurn(player.Id)
+	}
+	return nil
+}
+
+// finishAction は入力への回答を適用し終えたプレイヤーの後処理を行う。
+// fromから別のマスへ移動していれば移動先のマスの効果を処理し、そうでなければ変化を通知して手番を終える。
+func (gm *GameManager) finishAction(player *sugoroku.Player, from *sugoroku.Tile, r *statusReporter) error {
+	if player.Position != from {
+		return gm.settleMove(player, r)
+	}
+	r.report()
+	gm.endTurn(player.Id)
+	return nil
+}
+
+// resolveLanding はプレイヤーが止まったマスの効果を処理する。
+// 進む・戻る・ワープなどで別のマスへ移った場合は、PLAYER_MOVEDを通知してから移動先のマスの効果も続けて処理する。
+func (gm *GameManager) resolveLanding(player *sugoroku.Player, r *statusReporter) (landingResult, error) {
+	for i := 0; i < maxLandingChain; i++ {
+		tile := player.Position
+		result, err := gm.landOn(player, tile.Effect, r)
+		if err != nil || result != landingDone {
+			return result, err
+		}
+		if player.Position == tile {
+			return landingDone, nil
+		}
+		log.WithFields(log.Fields{
+			"playerID":    player.Id,
+			"from":        tile.Id,
+			"newPosition": player.Position.Id,
+		}).Info("Player moved by tile effect")
+	}
+
+	log.WithFields(log.Fields{
+		"playerID": player.Id,
+		"position": player.Position.Id,
+	}).Warn("Too many chained movement tiles, stopping here")
+	return landingDone, nil
+}
+
+// landOn は止まったマスの効果を1つ処理する。
+// 入力が必要な効果は入力を求め、止まったときの処理が登録された効果はその処理を、それ以外は効果を適用する。
+func (gm *GameManager) landOn(player *sugoroku.Player, effect sugoroku.EffectType, r *statusReporter) (landingResult, error) {
+	if in, ok := interactionFor(effect); ok {
+		return gm.requestInput(player, in)
+	}
+	if h, ok := effectHandlerFor(effect); ok && h.land != nil {
+		return h.land(gm, player, effect, r)
+	}
+	if effect.RequiresUserInput() {
+		return landingDone, fmt.Errorf("unhandled user input required for effect type %T", effect)
+	}
+	return landingDone, gm.applyEffect(player, effect, nil, r)
+}
+
+// applyEffect は効果を適用して変化を通知する。
+// 適用するときの処理が登録された効果(組み合わせの効果やくじ引きなど)は、その処理に任せる。
+func (gm *GameManager) applyEffect(player *sugoroku.Player, effect sugoroku.EffectType, choice any, r *statusReporter) error {
+	if h, ok := effectHandlerFor(effect); ok && h.apply != nil {
+		return h.apply(gm, player, effect, r)
+	}
+	if err := effect.Apply(player, gm.game, choice); err != nil {
+		return err
+	}
+	r.report()
+	return nil
+}
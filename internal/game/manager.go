@@ -9,6 +9,7 @@ import (
 	"cloud.google.com/go/firestore"
 	"firebase.google.com/go/v4/auth"
 	"github.com/shii-park/Metasugo-Backend/internal/hub"
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
 	"github.com/shii-park/Metasugo-Backend/internal/service"
 	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
 	log "github.com/sirupsen/logrus"
@@ -20,6 +21,11 @@ type GameManager struct {
 	playerClients map[string]*hub.Client
 	firestore     *firestore.Client
 	authClient    *auth.Client
+	turnBased     bool                // trueの場合は手番制で進行する
+	pending       map[string]*Pending // プレイヤーごとの未回答の入力要求
+	timeouts      DecisionTimeouts    // 入力要求の種類ごとの回答期限
+	journal       journal.Journal     // コマンドとイベントの記録先(nilの場合は記録しない)
+	closed        bool                // Stop後はコマンドを受け付けない
 	mu            sync.RWMutex
 }
 
@@ -36,10 +42,43 @@ func NewGameManager(g *sugoroku.Game, h *hub.Hub) *GameManager {
 		game:          g,
 		hub:           h,
 		playerClients: make(map[string]*hub.Client),
+		pending:       make(map[string]*Pending),
 		firestore:     fs,
 		authClient:    ac,
 	}
 }
+
+// NewHeadlessGameManager はHubやFirebaseに接続しないGameManagerを生成する。
+// ジャーナルの再生やシミュレーションなど、クライアントのいない環境でゲームを進めるために使う。
+func NewHeadlessGameManager(g *sugoroku.Game) *GameManager {
+	return &GameManager{
+		game:          g,
+		playerClients: make(map[string]*hub.Client),
+		pending:       make(map[string]*Pending),
+	}
+}
+
+// Stop は回答期限のタイマーをすべて止め、以降のコマンドを受け付けないようにする。
+// 部屋を閉じるときに、ジャーナルを閉じる前に呼ぶ。
+func (gm *GameManager) Stop() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	for _, p := range gm.pending {
+		if p.timer != nil {
+			p.timer.Stop()
+		}
+	}
+	gm.closed = true
+}
+
+// checkOpen はStop後でないことを確認する
+func (gm *GameManager) checkOpen() error {
+	if gm.closed {
+		return ErrRoomClosed
+	}
+	return nil
+}
+
 func (gm *GameManager) MoveByDiceRoll(playerID string, steps int) error {
 	player, err := gm.game.GetPlayer(playerID)
 	if err != nil {
@@ -47,77 +86,25 @@ func (gm *GameManager) MoveByDiceRoll(playerID string, steps int) error {
 	}
 
 	// 1. 移動前の状態を記録
-	initialPosition := player.Position.Id
-	initialMoney := player.Money
-	initialIsMarried := player.IsMarried
-	initialHasChildren := player.HasChildren
-	initialJob := player.Job
+	r := gm.newStatusReporter(player)
 
-	// 2. プレイヤーを移動させる
-	flag := player.Move(steps) //めんどくさくなったのでフラグで実装してる。Effect型で比較するなどもっといいやり方はあると思う
+	// 2. 関所に足止めされている場合は、振った目で判定して通過できたときだけ進む
+	player.RecordRoll(steps)
+	passed, err := gm.passHeldGate(player)
+	if err != nil {
+		return err
+	}
+	if !passed {
+		return gm.finishAction(player, player.Position, r)
+	}
 
-	// 効果を判定
+	// 3. プレイヤーを移動させ、止まったマスの効果を判定・適用
+	player.Move(steps)
 	log.WithFields(log.Fields{
 		"playerID":    playerID,
 		"newPosition": player.Position.Id,
 	}).Info("Player moved")
-	// 3. マス効果を判定・適用
-
-	finalPosition := player.Position.Id
-
-	if initialPosition != finalPosition {
-		gm.broadcastPlayerMoved(playerID, finalPosition)
-	}
-
-	currentTile := player.Position
-	effect := currentTile.Effect
-
-	if effect.RequiresUserInput() || flag == "GOAL" {
-		// ユーザからの入力が必要な場合、もしくはゴールの場合こちらで処理
-		switch e := effect.(type) {
-		case sugoroku.BranchEffect:
-			return gm.sendBranchSelection(player, currentTile, e)
-		case sugoroku.QuizEffect:
-			return gm.sendQuizInfo(player, currentTile, e)
-		case sugoroku.GambleEffect:
-			return gm.sendGambleRequire(player, currentTile)
-		case sugoroku.GoalEffect:
-			if err := gm.Goal(playerID, gm.playerClients[playerID]); err != nil { // TODO: ゴールした際に行う処理(clientとの接続解除など)を行ったほうが良いと思う
-				return err
-			}
-			return nil //ゲーム終了するのでここで関数を脱出！
-		default:
-			return fmt.Errorf("unhandled user input required for effect type %T", e)
-		}
-	} else {
-		// 即時効果を適用
-		if err := effect.Apply(player, gm.game, nil); err != nil {
-			return err
-		}
-	}
-
-	// 4. ステータスの変更を検知して通知
-	finalMoney := player.Money
-	if initialMoney != finalMoney {
-		gm.broadcastMoneyChanged(playerID, finalMoney)
-	}
-
-	finalIsMarried := player.IsMarried
-	if initialIsMarried != finalIsMarried {
-		gm.broadcastPlayerStatusChanged(playerID, "isMarried", finalIsMarried)
-	}
-
-	finalHasChildren := player.HasChildren
-	if initialHasChildren != finalHasChildren {
-		gm.broadcastPlayerStatusChanged(playerID, "hasChildren", finalHasChildren)
-	}
-
-	finalJob := player.Job
-	if initialJob != finalJob {
-		gm.broadcastPlayerStatusChanged(playerID, "job", finalJob)
-	}
-
-	return nil
+	return gm.settleMove(player, r)
 }
 
 func (gm *GameManager) GetAllPlayerStatuses() map[string]map[string]interface{} {
@@ -137,11 +124,25 @@ func (gm *GameManager) GetAllPlayerStatuses() map[string]map[string]interface{}
 func (gm *GameManager) RegisterPlayerClient(playerID string, c *hub.Client) error {
 	gm.mu.Lock()
 	defer gm.mu.Unlock()
+	gm.record(journal.KindCommand, CommandPlayerJoined, playerID, nil)
+
+	// スナップショットから復元されたプレイヤーは、続きから遊べるように接続だけ割り当てる
+	if player, err := gm.game.GetPlayer(playerID); err == nil {
+		if _, connected := gm.playerClients[playerID]; !connected {
+			return gm.reattachPlayer(player, c)
+		}
+	}
+
 	_, err := gm.game.AddPlayer(playerID)
 	if err != nil {
 		return err
 	}
 	gm.playerClients[playerID] = c
+
+	// 最初のプレイヤーが参加した時点で手番を開始する
+	if gm.turnBased && len(gm.game.GetAllPlayers()) == 1 {
+		gm.broadcastTurnStarted(playerID)
+	}
 	return nil
 }
 
@@ -149,6 +150,8 @@ func (gm *GameManager) UnregisterPlayerClient(playerID string, c *hub.Client) er
 	gm.mu.Lock()
 	defer gm.mu.Unlock()
 
+	gm.record(journal.KindCommand, CommandPlayerLeft, playerID, nil)
+
 	// 既に登録解除されていないか確認
 	if _, ok := gm.playerClients[playerID]; !ok {
 		log.WithField("playerID", playerID).Warn("Attempted to unregister an already unregistered player.")
@@ -164,6 +167,7 @@ func (gm *GameManager) unregisterPlayerClientLocked(playerID string, c *hub.Clie
 
 	// ゲームからプレイヤーを削除
 	log.WithField("playerID", playerID).Info("UnregisterPlayerClient: Deleting player from game")
+	hadTurn := gm.turnBased && gm.game.CurrentTurn() == playerID
 	err := gm.game.DeletePlayer(playerID)
 	if err != nil {
 		log.WithError(err).WithField("playerID", playerID).Error("UnregisterPlayerClient: Failed to delete player from game")
@@ -171,15 +175,26 @@ func (gm *GameManager) unregisterPlayerClientLocked(playerID string, c *hub.Clie
 	}
 	log.WithField("playerID", playerID).Info("UnregisterPlayerClient: Player deleted from game")
 
+	// 手番のプレイヤーが抜けた場合は次のプレイヤーに手番を渡す
+	if hadTurn {
+		gm.broadcastTurnEnded(playerID)
+		if next := gm.game.CurrentTurn(); next != "" {
+			gm.broadcastTurnStarted(next)
+		}
+	}
+
 	// GameManagerからプレイヤーを削除
 	delete(gm.playerClients, playerID)
+	gm.clearPending(playerID)
 	log.WithField("playerID", playerID).Info("UnregisterPlayerClient: Player deleted from playerClients map")
 
 	// Hubにクライアントの登録解除を通知
 	// これにより、Hubはクライアントの接続を閉じ、リソースを解放します
-	log.WithField("playerID", playerID).Info("UnregisterPlayerClient: Calling Hub.Unregister")
-	c.Hub.Unregister(c)
-	log.WithField("playerID", playerID).Info("UnregisterPlayerClient: Hub.Unregister completed")
+	if c != nil {
+		log.WithField("playerID", playerID).Info("UnregisterPlayerClient: Calling Hub.Unregister")
+		c.Hub.Unregister(c)
+		log.WithField("playerID", playerID).Info("UnregisterPlayerClient: Hub.Unregister completed")
+	}
 
 	return nil
 }
@@ -195,15 +210,38 @@ func (gm *GameManager) Goal(playerID string, c *hub.Client) error {
 	money := player.Money
 	log.WithFields(log.Fields{"playerID": playerID, "money": money}).Info("Player retrieved successfully")
 
-	fmt.Printf("%s goal!!!!!!!", playerID)
+	if err := gm.saveClearData(playerID, money); err != nil {
+		return err
+	}
+
+	log.Info("Broadcasting player finished")
+	gm.broadcastPlayerFinished(playerID, money)
+	log.Info("Broadcast completed")
+
+	// 同期的に登録解除を行う
+	log.Info("Calling unregisterPlayerClientLocked synchronously")
+	if err := gm.unregisterPlayerClientLocked(playerID, c); err != nil {
+		log.WithError(err).Error("unregisterPlayerClientLocked failed")
+	} else {
+		log.Info("unregisterPlayerClientLocked completed successfully")
+	}
+
+	return nil
+}
+
+// saveClearData はゴールしたプレイヤーの記録をFirestoreに保存する。
+// Firestoreに接続していない場合(ジャーナルの再生時など)は何もしない。
+func (gm *GameManager) saveClearData(playerID string, money int) error {
+	if gm.firestore == nil || gm.authClient == nil {
+		log.WithField("playerID", playerID).Info("Firestore is not configured, skipping clear data save")
+		return nil
+	}
+
 	var displayName string
 	ctxAuth, cancelAuth := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancelAuth()
 
 	userRecord, err := gm.authClient.GetUser(ctxAuth, playerID)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-
 	if err != nil {
 		log.WithError(err).Errorf("Authからユーザー情報取得失敗 (UID: %s)", playerID)
 		displayName = "（名前不明）" // エラー時のフォールバック
@@ -213,7 +251,6 @@ func (gm *GameManager) Goal(playerID string, c *hub.Client) error {
 			log.Warnf("Authにユーザーは存在するがDisplayName未設定 (UID: %s)", playerID)
 			displayName = "（名前なし）" // DisplayNameが空の場合のフォールバック
 		}
-		defer cancel()
 	}
 	// Firestoreに保存するデータを作成
 	data := map[string]interface{}{
@@ -224,6 +261,9 @@ func (gm *GameManager) Goal(playerID string, c *hub.Client) error {
 	}
 
 	// Firestoreにデータを保存
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
 	log.Info("Starting Firestore save")
 	_, _, err = gm.firestore.Collection("playerClearData").Add(ctx, data)
 	if err != nil {
@@ -231,18 +271,5 @@ func (gm *GameManager) Goal(playerID string, c *hub.Client) error {
 		return fmt.Errorf("failed to save player data to firestore: %w", err)
 	}
 	log.Info("Firestore save completed")
-
-	log.Info("Broadcasting player finished")
-	gm.broadcastPlayerFinished(playerID, money)
-	log.Info("Broadcast completed")
-
-	// 同期的に登録解除を行う
-	log.Info("Calling unregisterPlayerClientLocked synchronously")
-	if err := gm.unregisterPlayerClientLocked(playerID, c); err != nil {
-		log.WithError(err).Error("unregisterPlayerClientLocked failed")
-	} else {
-		log.Info("unregisterPlayerClientLocked completed successfully")
-	}
-
 	return nil
 }
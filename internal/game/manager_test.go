@@ -121,7 +121,7 @@ func TestGameManager_SendsQuizRequired(t *testing.T) {
 	assert.NoError(t, err)
 
 	// player1がQUIZ_REQUIREDイベントを受信することを確認
-	payload := assertEventReceived(t, player1, "QUIZ_REQUIRED")
+	payload := waitForEvent(t, player1, "QUIZ_REQUIRED")
 	assert.Equal(t, float64(3), payload["tileID"])
 	quizData, ok := payload["quizData"].(map[string]any)
 	assert.True(t, ok)
@@ -142,7 +142,7 @@ func TestGameManager_SendsBranchChoiceRequired(t *testing.T) {
 	assert.NoError(t, err)
 
 	// player1がBRANCH_CHOICE_REQUIREDイベントを受信することを確認
-	payload := assertEventReceived(t, player1, "BRANCH_CHOICE_REQUIRED")
+	payload := waitForEvent(t, player1, "BRANCH_CHOICE_REQUIRED")
 	assert.Equal(t, float64(4), payload["tileID"])
 	options, ok := payload["options"].([]any)
 	assert.True(t, ok)
@@ -163,6 +163,7 @@ func TestHandleBranch_Effect(t *testing.T) {
 	branchTile, err := gm.game.GetTile(1)
 	assert.NoError(t, err)
 	player.Position = branchTile
+	gm.setPending(playerID, pendingBranch, branchTile.Id)
 
 	initialMoney := player.Money
 
@@ -175,3 +176,576 @@ func TestHandleBranch_Effect(t *testing.T) {
 	assert.Equal(t, initialMoney+100, player.Money)
 }
 
+// waitForEvent は指定したイベントを受信するまで他のイベントを読み飛ばします。
+func waitForEvent(t *testing.T, client *hub.Client, expectedEventType string) map[string]any {
+	t.Helper()
+	timeout := time.After(200 * time.Millisecond)
+	for {
+		select {
+		case msg := <-client.Send:
+			var event map[string]any
+			assert.NoError(t, json.Unmarshal(msg, &event))
+			if event["type"] != expectedEventType {
+				continue
+			}
+			payload, _ := event["payload"].(map[string]any)
+			return payload
+		case <-timeout:
+			t.Fatalf("Timed out waiting for %s event", expectedEventType)
+			return nil
+		}
+	}
+}
+
+// waitForEvents は指定した種類のイベントをすべて受信するまで待つ。
+// ブロードキャストと個別の送信は届く順番が前後するため、順番を問わずに待つ場合に使う。
+func waitForEvents(t *testing.T, client *hub.Client, eventTypes ...string) map[string]map[string]any {
+	t.Helper()
+	received := make(map[string]map[string]any)
+	timeout := time.After(200 * time.Millisecond)
+	for len(received) < len(eventTypes) {
+		select {
+		case msg := <-client.Send:
+			var event map[string]any
+			assert.NoError(t, json.Unmarshal(msg, &event))
+			eventType, _ := event["type"].(string)
+			for _, want := range eventTypes {
+				if eventType == want {
+					received[eventType], _ = event["payload"].(map[string]any)
+				}
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for %v events, received %d", eventTypes, len(received))
+			return nil
+		}
+	}
+	return received
+}
+
+func TestGameManager_TurnOrder(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	gm.SetTurnBased(true)
+
+	player1 := createAndRegisterClient(t, gm, h, "player1")
+	_ = createAndRegisterClient(t, gm, h, "player2")
+
+	payload := waitForEvent(t, player1, "TURN_STARTED")
+	assert.Equal(t, "player1", payload["userID"])
+	assert.Equal(t, "player1", gm.CurrentTurn())
+
+	// 手番でないプレイヤーはサイコロを振れない
+	err := gm.HandleMove("player2")
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+
+	// 効果の解決が終わると手番が進む(利益マスに止まる)
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 1))
+	payload = waitForEvent(t, player1, "TURN_ENDED")
+	assert.Equal(t, "player1", payload["userID"])
+	payload = waitForEvent(t, player1, "TURN_STARTED")
+	assert.Equal(t, "player2", payload["userID"])
+
+	// 入力が必要なマスでは、解決するまで手番が進まない(クイズマスに止まる)
+	assert.NoError(t, gm.MoveByDiceRoll("player2", 2))
+	assert.Equal(t, "player2", gm.CurrentTurn())
+}
+
+func TestGameManager_PendingAction(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	_ = createAndRegisterClient(t, gm, h, "player1")
+
+	// 入力要求がない状態では回答を受け付けない
+	err := gm.HandleGamble("player1", map[string]any{"bet": float64(10), "choice": "High"})
+	assert.ErrorIs(t, err, ErrUnexpectedSubmit)
+
+	// クイズマスに止まるとクイズの回答待ちになる
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 2))
+
+	// 回答するまではサイコロを振れない
+	assert.ErrorIs(t, gm.HandleMove("player1"), ErrPendingAction)
+
+	// 種類の違う回答は受け付けない
+	err = gm.HandleGamble("player1", map[string]any{"bet": float64(10), "choice": "High"})
+	assert.ErrorIs(t, err, ErrUnexpectedSubmit)
+	err = gm.HandleBranch("player1", map[string]any{"selection": float64(4)})
+	assert.ErrorIs(t, err, ErrUnexpectedSubmit)
+	assert.False(t, IsSubmitCommand("SUBMIT_TELEPORT"))
+	assert.Error(t, gm.HandleSubmit("SUBMIT_TELEPORT", "player1", map[string]any{}))
+	assert.Contains(t, gm.pending, "player1")
+
+	// 対応する回答を受け付けると回答待ちが解除される
+	assert.NoError(t, gm.HandleQuiz("player1", map[string]any{"quizID": float64(1), "selection": float64(1)}))
+	assert.NotContains(t, gm.pending, "player1")
+	err = gm.HandleQuiz("player1", map[string]any{"quizID": float64(1), "selection": float64(1)})
+	assert.ErrorIs(t, err, ErrUnexpectedSubmit)
+}
+
+func TestGameManager_ChainedMovementTiles(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "advance", "effect": {"type": "advance", "steps": 2}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "normal", "prev_ids": [2], "next_ids": [4]},
+		{"id": 4, "kind": "warp", "effect": {"type": "warp", "tile_id": 6}, "prev_ids": [3], "next_ids": [5]},
+		{"id": 5, "kind": "normal", "prev_ids": [4], "next_ids": [6]},
+		{"id": 6, "kind": "quiz", "effect": {"type": "quiz", "quiz_id": 1}, "prev_ids": [5], "next_ids": [7]},
+		{"id": 7, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [6], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+
+	// マス2(1マス進む) → マス4(ワープ) → マス6(クイズ) と続けて移動する
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 1))
+
+	// 全体通知と個別の送信は届く順番が前後するので、種類ごとに集める
+	var moved []any
+	var quizTileID any
+	timeout := time.After(200 * time.Millisecond)
+	for len(moved) < 3 || quizTileID == nil {
+		select {
+		case msg := <-client.Send:
+			var event map[string]any
+			assert.NoError(t, json.Unmarshal(msg, &event))
+			payload, _ := event["payload"].(map[string]any)
+			switch event["type"] {
+			case "PLAYER_MOVED":
+				moved = append(moved, payload["newPosition"])
+			case "QUIZ_REQUIRED":
+				quizTileID = payload["tileID"]
+			}
+		case <-timeout:
+			t.Fatalf("Timed out: moved=%v quiz=%v", moved, quizTileID)
+		}
+	}
+	assert.Equal(t, []any{float64(2), float64(4), float64(6)}, moved)
+	assert.Equal(t, float64(6), quizTileID)
+
+	// 入力待ちになるので手番は終わらない
+	if assert.Contains(t, gm.pending, "player1") {
+		assert.Equal(t, pendingQuiz, gm.pending["player1"].kind)
+		assert.Equal(t, 6, gm.pending["player1"].tileID)
+	}
+}
+
+func TestGameManager_GateHoldsPlayer(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "require", "effect": {"type": "require", "mode": "min_dice", "require_value": 4}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "normal", "prev_ids": [2], "next_ids": [4]},
+		{"id": 4, "kind": "normal", "prev_ids": [3], "next_ids": [5]},
+		{"id": 5, "kind": "normal", "prev_ids": [4], "next_ids": [6]},
+		{"id": 6, "kind": "normal", "prev_ids": [5], "next_ids": [7]},
+		{"id": 7, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [6], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+	player, err := gm.game.GetPlayer("player1")
+	assert.NoError(t, err)
+	// 関所の判定に別のサイコロを振らないことを確かめるため、振ると通過できる目にしておく
+	assert.NoError(t, gm.game.ConfigureDice(sugoroku.DiceConfig{Fixed: []int{6}}))
+
+	// 関所には手番で振った3の目で挑むので、足止めされる
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 3))
+	payload := waitForEvent(t, client, "GATE_RESULT")
+	assert.Equal(t, false, payload["passed"])
+	assert.Equal(t, true, payload["held"])
+	assert.Equal(t, float64(3), payload["diceResult"])
+	assert.Equal(t, 2, player.Position.Id)
+
+	// 足止め中は関所を通過できる目が出るまで進めない
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 2))
+	payload = waitForEvent(t, client, "GATE_RESULT")
+	assert.Equal(t, float64(2), payload["diceResult"])
+	assert.Equal(t, 2, player.Position.Id)
+
+	// 関所を通過すると、振った目の分だけ進む
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 4))
+	payload = waitForEvent(t, client, "GATE_RESULT")
+	assert.Equal(t, true, payload["passed"])
+	assert.False(t, player.IsHeld())
+	assert.Equal(t, 6, player.Position.Id)
+}
+
+func TestGameManager_EffectChoice(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "choice", "effect": {"type": "choice", "options": [
+			{"label": "結婚する", "effect": {"type": "sequence", "effects": [
+				{"type": "setStatus", "status": "isMarried", "value": true},
+				{"type": "profit", "amount": 300000}
+			]}},
+			{"label": "何もしない", "effect": null}
+		]}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 1))
+	payload := waitForEvent(t, client, "EFFECT_CHOICE_REQUIRED")
+	assert.Equal(t, float64(2), payload["tileID"])
+	assert.Len(t, payload["options"], 2)
+
+	// 種類の違う回答と、存在しない選択肢は受け付けない
+	assert.ErrorIs(t, gm.HandleBranch("player1", map[string]any{"selection": float64(3)}), ErrUnexpectedSubmit)
+	assert.Error(t, gm.HandleEffectChoice("player1", map[string]any{"selection": float64(5)}))
+
+	// 中の効果ごとに変化が通知される
+	assert.NoError(t, gm.HandleEffectChoice("player1", map[string]any{"selection": float64(0)}))
+	status := waitForEvent(t, client, "PLAYER_STATUS_CHANGED")
+	assert.Equal(t, "isMarried", status["status"])
+	money := waitForEvent(t, client, "MONEY_CHANGED")
+	assert.Equal(t, float64(1300000), money["newMoney"])
+	assert.NotContains(t, gm.pending, "player1")
+}
+
+func TestGameManager_LotteryResult(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "lottery", "effect": {"type": "lottery", "outcomes": [
+			{"label": "晴れ", "weight": 1, "effect": {"type": "profit", "amount": 100}}
+		]}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 1))
+	payload := waitForEvent(t, client, "LOTTERY_RESULT")
+	assert.Equal(t, "player1", payload["userID"])
+	assert.Equal(t, float64(2), payload["tileID"])
+	assert.Equal(t, float64(0), payload["index"])
+	assert.Equal(t, "晴れ", payload["label"])
+	money := waitForEvent(t, client, "MONEY_CHANGED")
+	assert.Equal(t, float64(1000100), money["newMoney"])
+}
+
+func TestGameManager_LotteryInConditional(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "conditional", "effect": {"type": "conditional", "condition": "money > 0",
+			"true_effect": {"type": "lottery", "outcomes": [
+				{"label": "当たり", "weight": 1, "effect": {"type": "profit", "amount": 100}}
+			]}}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+
+	// 条件分岐の中のくじ引きも抽選結果を通知する
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 1))
+	payload := waitForEvent(t, client, "LOTTERY_RESULT")
+	assert.Equal(t, float64(2), payload["tileID"])
+	assert.Equal(t, "当たり", payload["label"])
+	money := waitForEvent(t, client, "MONEY_CHANGED")
+	assert.Equal(t, float64(1000100), money["newMoney"])
+}
+
+func TestGameManager_BranchKeepsRemainingSteps(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [1], "next_ids": [3, 5]},
+		{"id": 3, "kind": "normal", "prev_ids": [2], "next_ids": [4]},
+		{"id": 4, "kind": "normal", "prev_ids": [3], "next_ids": [7]},
+		{"id": 5, "kind": "normal", "prev_ids": [2], "next_ids": [6]},
+		{"id": 6, "kind": "profit", "effect": {"type": "profit", "amount": 10}, "prev_ids": [5], "next_ids": [7]},
+		{"id": 7, "kind": "profit", "effect": {"type": "profit", "amount": 100}, "prev_ids": [4, 6], "next_ids": [8]},
+		{"id": 8, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [7], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+	player, err := gm.game.GetPlayer("player1")
+	assert.NoError(t, err)
+
+	// 4の目で1歩目の分岐に止まり、残りの3歩は道を選ぶまで保留される
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 4))
+	waitForEvent(t, client, "BRANCH_CHOICE_REQUIRED")
+	assert.Equal(t, 3, gm.pending["player1"].steps)
+	assert.Equal(t, 3, gm.Snapshot().Pending["player1"].Steps)
+
+	// 選んだマスへの1歩を含めて3歩進み、途中のマスの効果は発生しない
+	assert.NoError(t, gm.HandleBranch("player1", map[string]any{"selection": float64(5)}))
+	assert.Equal(t, 7, player.Position.Id)
+	assert.Equal(t, 1000100, player.Money)
+
+	// 分岐までの移動の通知は読み飛ばす
+	payload := waitForEvent(t, client, "PLAYER_MOVED")
+	for payload["newPosition"] == float64(2) {
+		payload = waitForEvent(t, client, "PLAYER_MOVED")
+	}
+	assert.Equal(t, float64(7), payload["newPosition"])
+	assert.Equal(t, []any{float64(5), float64(6), float64(7)}, payload["path"])
+}
+
+func TestHandleBranch_ResolvesInteractiveTiles(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [], "next_ids": [2, 3, 4, 5]},
+		{"id": 2, "kind": "quiz", "effect": {"type": "quiz", "quiz_id": 1}, "prev_ids": [1], "next_ids": [6]},
+		{"id": 3, "kind": "gamble", "effect": {"type": "gamble"}, "prev_ids": [1], "next_ids": [6]},
+		{"id": 4, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [1], "next_ids": [6, 7]},
+		{"id": 5, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [1], "next_ids": []},
+		{"id": 6, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2, 3, 4], "next_ids": []},
+		{"id": 7, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [4], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+
+	cases := []struct {
+		selection   int
+		wantEvent   string
+		wantPending PendingKind
+	}{
+		{selection: 2, wantEvent: "QUIZ_REQUIRED", wantPending: pendingQuiz},
+		{selection: 3, wantEvent: "GAMBLE_REQUIRED", wantPending: pendingGamble},
+		{selection: 4, wantEvent: "BRANCH_CHOICE_REQUIRED", wantPending: pendingBranch},
+		{selection: 5, wantEvent: "PLAYER_FINISHED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.wantEvent, func(t *testing.T) {
+			gm, h := setupTestEnvironment(t, tilePath)
+			// ゴールの記録はFirestoreに保存しない
+			gm.firestore, gm.authClient = nil, nil
+			client := createAndRegisterClient(t, gm, h, "player1")
+			gm.setPending("player1", pendingBranch, 1)
+
+			assert.NoError(t, gm.HandleBranch("player1", map[string]any{"selection": float64(tc.selection)}))
+			waitForEvent(t, client, tc.wantEvent)
+			if tc.wantPending == "" {
+				assert.NotContains(t, gm.pending, "player1")
+				return
+			}
+			if assert.Contains(t, gm.pending, "player1") {
+				assert.Equal(t, tc.wantPending, gm.pending["player1"].kind)
+				assert.Equal(t, tc.selection, gm.pending["player1"].tileID)
+			}
+		})
+	}
+}
+
+func TestGameManager_BroadcastsMoneyTransfers(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "overall", "effect": {"type": "overall", "profit_amount": 100}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+	_ = createAndRegisterClient(t, gm, h, "player2")
+	_ = createAndRegisterClient(t, gm, h, "player3")
+
+	// player1が2人から100ずつ受け取る
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 1))
+
+	changed := make(map[any]any)
+	for len(changed) < 3 {
+		payload := waitForEvent(t, client, "MONEY_CHANGED")
+		changed[payload["userID"]] = payload["newMoney"]
+	}
+	assert.Equal(t, map[any]any{
+		"player1": float64(1000200),
+		"player2": float64(999900),
+		"player3": float64(999900),
+	}, changed)
+
+	payload := waitForEvent(t, client, "MONEY_TRANSFERS")
+	assert.Equal(t, "player1", payload["causedBy"])
+	assert.Equal(t, float64(2), payload["tileID"])
+	assert.Equal(t, []any{
+		map[string]any{"userID": "player1", "delta": float64(200), "newMoney": float64(1000200)},
+		map[string]any{"userID": "player2", "delta": float64(-100), "newMoney": float64(999900)},
+		map[string]any{"userID": "player3", "delta": float64(-100), "newMoney": float64(999900)},
+	}, payload["transfers"])
+}
+
+func TestGameManager_QuizGradedAsAsked(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+	original := sugoroku.LoadedQuizzes()
+	t.Cleanup(func() { sugoroku.SetQuizzes(original) })
+
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 2))
+	waitForEvent(t, client, "QUIZ_REQUIRED")
+
+	// 出題した後にクイズが差し替えられても、出題したときの答えで採点する
+	sugoroku.SetQuizzes([]sugoroku.Quiz{{ID: 1, Question: "1 + 2は？", Options: []string{"1", "2", "3"}, AnswerIndex: 2}})
+	assert.NoError(t, gm.HandleQuiz("player1", map[string]any{"selection": float64(1)}))
+	result := waitForEvent(t, client, "QUIZ_RESULT")
+	assert.Equal(t, true, result["correct"])
+	assert.Equal(t, "答えは2です。", result["answer_description"])
+}
+
+func TestGameManager_QuizResult(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+
+	// クイズマス(ID:3)に止まると、答えを除いた問題が送られる
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 2))
+	payload := waitForEvent(t, client, "QUIZ_REQUIRED")
+	quizData, ok := payload["quizData"].(map[string]any)
+	assert.True(t, ok)
+	assert.NotContains(t, quizData, "answerIndex")
+	assert.NotContains(t, quizData, "answer_description")
+	assert.Equal(t, 1, gm.pending["player1"].quiz.ID)
+
+	// 出題していないクイズへの回答は受け付けない
+	err := gm.HandleQuiz("player1", map[string]any{"quizID": float64(2), "selection": float64(1)})
+	assert.Error(t, err)
+	assert.Contains(t, gm.pending, "player1")
+
+	// 出題したクイズへの回答は1回だけ受け付け、採点結果が送られる
+	assert.NoError(t, gm.HandleQuiz("player1", map[string]any{"selection": float64(0)}))
+	result := waitForEvent(t, client, "QUIZ_RESULT")
+	assert.Equal(t, float64(3), result["tileID"])
+	assert.Equal(t, float64(1), result["quizID"])
+	assert.Equal(t, false, result["correct"])
+	assert.Equal(t, float64(1), result["answerIndex"])
+	assert.Equal(t, "答えは2です。", result["answer_description"])
+	assert.ErrorIs(t, gm.HandleQuiz("player1", map[string]any{"selection": float64(1)}), ErrUnexpectedSubmit)
+}
+
+func TestGameManager_DecisionTimeout(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	gm.SetDecisionTimeouts(DecisionTimeouts{pendingBranch: 50 * time.Millisecond, pendingQuiz: 50 * time.Millisecond})
+	client := createAndRegisterClient(t, gm, h, "player1")
+	player, err := gm.game.GetPlayer("player1")
+	assert.NoError(t, err)
+	money := player.Money
+
+	gm.game.SetDice(sugoroku.NewFixedDice(2, 1))
+
+	// クイズの入力要求には回答期限が付く
+	assert.NoError(t, gm.HandleMove("player1"))
+	payload := waitForEvent(t, client, "QUIZ_REQUIRED")
+	assert.NotEmpty(t, payload["deadline"])
+
+	// 回答しないまま期限が過ぎると不正解として扱われる
+	events := waitForEvents(t, client, "DECISION_TIMEOUT", "QUIZ_RESULT")
+	assert.Equal(t, "quiz", events["DECISION_TIMEOUT"]["kind"])
+	assert.Equal(t, float64(3), events["DECISION_TIMEOUT"]["tileID"])
+	assert.Equal(t, false, events["QUIZ_RESULT"]["correct"])
+	assert.Equal(t, float64(-1), events["QUIZ_RESULT"]["selection"])
+
+	// 分岐で回答しないまま期限が過ぎると最初の選択肢へ進む
+	assert.NoError(t, gm.HandleMove("player1"))
+	waitForEvent(t, client, "BRANCH_CHOICE_REQUIRED")
+	timeout := waitForEvent(t, client, "DECISION_TIMEOUT")
+	assert.Equal(t, "branch", timeout["kind"])
+	assert.Equal(t, float64(5), timeout["selection"])
+
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	assert.Equal(t, 5, player.Position.Id)
+	assert.Equal(t, money-50, player.Money)
+	assert.NotContains(t, gm.pending, "player1")
+}
+
+func TestGameManager_AnswerBeforeDeadline(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	gm.SetDecisionTimeouts(DecisionTimeouts{pendingQuiz: 50 * time.Millisecond})
+	_ = createAndRegisterClient(t, gm, h, "player1")
+	player, err := gm.game.GetPlayer("player1")
+	assert.NoError(t, err)
+
+	gm.game.SetDice(sugoroku.NewFixedDice(2))
+
+	// 期限までに回答すれば、期限が過ぎても既定の回答は適用されない
+	assert.NoError(t, gm.HandleMove("player1"))
+	gm.mu.RLock()
+	money := player.Money
+	gm.mu.RUnlock()
+	assert.NoError(t, gm.HandleQuiz("player1", map[string]any{"selection": float64(1)}))
+	time.Sleep(100 * time.Millisecond)
+
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	assert.Equal(t, money+50, player.Money)
+}
+
+func TestGameManager_GambleResult(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "gamble", "effect": {"type": "gamble", "game": "exact", "payout": 3, "max_bet_percent": 10}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+	]`
+	tilePath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilePath, []byte(boardJSON), 0o644))
+	gm, h := setupTestEnvironment(t, tilePath)
+	assert.NoError(t, gm.game.ConfigureDice(sugoroku.DiceConfig{Fixed: []int{1, 6}}))
+	client := createAndRegisterClient(t, gm, h, "player1")
+
+	// 賭け金の範囲と選べる目がマスの設定と所持金から決まる
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 1))
+	prompt := waitForEvent(t, client, "GAMBLE_REQUIRED")
+	assert.Equal(t, "exact", prompt["game"])
+	assert.Equal(t, float64(1), prompt["minBet"])
+	assert.Equal(t, float64(100000), prompt["maxBet"])
+	assert.Len(t, prompt["options"], 6)
+
+	// 上限を超える賭け金は受け付けない
+	err := gm.HandleGamble("player1", map[string]any{"bet": float64(100001), "choice": float64(1)})
+	assert.Error(t, err)
+	assert.Contains(t, gm.pending, "player1")
+
+	// 1の目を当てて賭け金の3倍をもらう
+	assert.NoError(t, gm.HandleGamble("player1", map[string]any{"bet": float64(100), "choice": float64(1)}))
+	result := waitForEvent(t, client, "GAMBLE_RESULT")
+	assert.Equal(t, true, result["won"])
+	assert.Equal(t, float64(1), result["diceResult"])
+	assert.Equal(t, float64(300), result["amount"])
+	assert.Equal(t, float64(1000300), result["newMoney"])
+}
+
+func TestGameManager_ReloadBoard(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	quizPath := getTestFilePath(t, "test/test_quizzes.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	client := createAndRegisterClient(t, gm, h, "player1")
+
+	// クイズマス(ID:3)で回答待ちにする
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 2))
+	waitForEvent(t, client, "QUIZ_REQUIRED")
+
+	// 同じ種類のマスのままなら、もう一度入力を求める
+	board, _, err := sugoroku.LoadBoard(tilePath, quizPath)
+	assert.NoError(t, err)
+	assert.NoError(t, gm.ReloadBoard(board))
+	events := waitForEvents(t, client, "BOARD_UPDATED", "QUIZ_REQUIRED")
+	assert.Equal(t, float64(len(board.Tiles)), events["BOARD_UPDATED"]["tileCount"])
+	assert.Equal(t, gm.Board().Hash, events["BOARD_UPDATED"]["hash"])
+	assert.Equal(t, float64(board.Version), events["BOARD_UPDATED"]["version"])
+	assert.Equal(t, []any{}, events["BOARD_UPDATED"]["relocated"])
+	assert.Contains(t, gm.pending, "player1")
+
+	// 入力が要らないマスに変わった場合は、回答待ちを取り消す
+	changedPath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(changedPath, []byte(`[
+		{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "normal", "effect": null, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "profit", "effect": {"type": "profit", "amount": 10}, "prev_ids": [2], "next_ids": [4]},
+		{"id": 4, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [3], "next_ids": []}
+	]`), 0o644))
+	board, _, err = sugoroku.LoadBoard(changedPath, quizPath)
+	assert.NoError(t, err)
+	assert.NoError(t, gm.ReloadBoard(board))
+	payload := waitForEvent(t, client, "BOARD_UPDATED")
+	assert.Equal(t, float64(4), payload["tileCount"])
+	assert.NotContains(t, gm.pending, "player1")
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"time"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// PendingKind はプレイヤーが回答を求められている入力の種類。
+// 種類ごとの定数は、入力の扱い方を登録するファイルで定義する。
+type PendingKind string
+
+// Pending はプレイヤーがまだ回答していない入力要求
+type Pending struct {
+	kind   PendingKind
+	tileID int
+	steps  int            // 分岐で止まったときのサイコロの残りの歩数
+	quiz   *sugoroku.Quiz // 出題したクイズ。出題後にクイズが差し替えられても、この内容で採点する
+
+	deadline time.Time       // 回答期限(期限なしの場合はゼロ値)
+	timer    *time.Timer     // 期限切れを処理するタイマー
+	reporter *statusReporter // 回答を適用している間の変化の通知
+}
+
+// Kind は入力要求の種類を返す
+func (p *Pending) Kind() PendingKind {
+	return p.kind
+}
+
+// TileID は入力を求めているマスのIDを返す
+func (p *Pending) TileID() int {
+	return p.tileID
+}
+
+// setPending はプレイヤーに入力要求を記録し、回答期限が設定されていればタイマーを開始する
+func (gm *GameManager) setPending(playerID string, kind PendingKind, tileID int) *Pending {
+	gm.clearPending(playerID)
+	p := &Pending{kind: kind, tileID: tileID}
+	gm.pending[playerID] = p
+	gm.startDeadline(playerID, p)
+	return p
+}
+
+// checkNoPending は未回答の入力要求がないことを確認する
+func (gm *GameManager) checkNoPending(playerID string) error {
+	if _, ok := gm.pending[playerID]; ok {
+		return ErrPendingAction
+	}
+	return nil
+}
+
+// checkPending は指定した種類の入力要求を受け付けられるかを確認する
+func (gm *GameManager) checkPending(playerID string, kind PendingKind) error {
+	p, ok := gm.pending[playerID]
+	if !ok || p.kind != kind {
+		return ErrUnexpectedSubmit
+	}
+	return nil
+}
+
+// clearPending は回答済みの入力要求を取り除き、回答期限のタイマーを止める
+func (gm *GameManager) clearPending(playerID string) {
+	if p, ok := gm.pending[playerID]; ok && p.timer != nil {
+		p.timer.Stop()
+	}
+	delete(gm.pending, playerID)
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// pendingQuiz はクイズマスで答えを選ぶ入力要求
+const pendingQuiz PendingKind = "quiz"
+
+func init() {
+	RegisterInteraction(sugoroku.QuizEffect{}, Interaction{
+		Kind:    pendingQuiz,
+		Command: CommandSubmitQuiz,
+		// 出題したクイズを覚えておき、そのクイズへの回答だけを受け付ける
+		Begin: func(gm *GameManager, player *sugoroku.Player, p *Pending) (bool, error) {
+			quiz := player.Position.Effect.(sugoroku.QuizEffect).Pick(gm.game)
+			if quiz == nil {
+				return false, fmt.Errorf("no quiz available for tile %d", player.Position.Id)
+			}
+			asked := *quiz
+			p.quiz = &asked
+			return true, nil
+		},
+		Prompt: func(gm *GameManager, player *sugoroku.Player, p *Pending) (map[string]any, error) {
+			if p.quiz == nil {
+				return nil, fmt.Errorf("no quiz served on tile %d", p.tileID)
+			}
+			return gm.quizInfoEvent(player.Position, p.quiz), nil
+		},
+		Submit: func(gm *GameManager, player *sugoroku.Player, p *Pending, payload map[string]any) error {
+			return gm.answerQuiz(player, p, payload)
+		},
+		// 期限切れの場合は不正解として扱う
+		Expire: func(gm *GameManager, player *sugoroku.Player, p *Pending, selection any) error {
+			tile := player.Position
+			effect, ok := tile.Effect.(sugoroku.QuizEffect)
+			if !ok {
+				return fmt.Errorf("tile %d is not a quiz tile", tile.Id)
+			}
+			if p.quiz == nil {
+				return fmt.Errorf("no quiz served on tile %d", tile.Id)
+			}
+			gm.sendQuizResult(player.Id, tile.Id, effect.Forfeit(player, *p.quiz))
+			return nil
+		},
+	})
+}
+
+// SUBMIT_QUIZリクエスト時に発火する関数。
+// ペイロードから答えを読み取り、出題したクイズについて採点する。
+func (gm *GameManager) HandleQuiz(playerID string, payload map[string]interface{}) error {
+	return gm.HandleSubmit(CommandSubmitQuiz, playerID, payload)
+}
+
+// answerQuiz は出題したクイズへの回答を採点し、結果をプレイヤーに送る
+func (gm *GameManager) answerQuiz(player *sugoroku.Player, p *Pending, payload map[string]any) error {
+	selection, ok := payload["selection"].(float64)
+	if !ok {
+		return errors.New("selection not found or is not a number in payload")
+	}
+	if p.quiz == nil {
+		return fmt.Errorf("no quiz served to player %s", player.Id)
+	}
+	// クイズIDは省略できるが、送られてきた場合は出題したクイズと一致しなければならない
+	if sent, ok := payload["quizID"].(float64); ok && int(sent) != p.quiz.ID {
+		return fmt.Errorf("quiz %d was not served to player %s", int(sent), player.Id)
+	}
+
+	currentTile := player.Position
+	effect, ok := currentTile.Effect.(sugoroku.QuizEffect)
+	if !ok {
+		return fmt.Errorf("tile %d is not a quiz tile", currentTile.Id)
+	}
+	result, err := effect.Answer(player, sugoroku.QuizAnswer{Quiz: *p.quiz, Selection: int(selection)})
+	if err != nil {
+		return fmt.Errorf("failed to apply quiz choice: %w", err)
+	}
+	gm.sendQuizResult(player.Id, currentTile.Id, result)
+	return nil
+}
+
+// quizInfoEvent は出題するクイズを答えを除いて送るイベントを作る
+func (gm *GameManager) quizInfoEvent(tile *sugoroku.Tile, quiz *sugoroku.Quiz) map[string]any {
+	return map[string]any{
+		"type": "QUIZ_REQUIRED",
+		"payload": map[string]any{
+			"tileID":   tile.Id,
+			"quizData": quiz.Public(),
+		},
+	}
+}
+
+// sendQuizResult はクイズの採点結果を回答したプレイヤーに送信
+func (gm *GameManager) sendQuizResult(playerID string, tileID int, result sugoroku.QuizResult) {
+	event := map[string]any{
+		"type": "QUIZ_RESULT",
+		"payload": map[string]any{
+			"userID":             playerID,
+			"tileID":             tileID,
+			"quizID":             result.QuizID,
+			"selection":          result.Selection,
+			"correct":            result.Correct,
+			"answerIndex":        result.AnswerIndex,
+			"answer_description": result.AnswerDescription,
+		},
+	}
+	if err := gm.sendToPlayer(playerID, event); err != nil {
+		log.WithFields(log.Fields{
+			"error":    err,
+			"playerID": playerID,
+		}).Error("failed to send quiz result to player")
+	}
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// ReloadPlan は差し替えの準備ができた盤面。Commitでゲームに反映する
+type ReloadPlan struct {
+	gm      *GameManager
+	board   *sugoroku.Board
+	tileMap map[int]*sugoroku.Tile
+}
+
+// ReloadBoard は進行中のゲームの盤面を差し替え、BOARD_UPDATEDを通知する。
+// プレイヤーは同じIDのマスへ移り、なくなったマスにいたプレイヤーはスタートに戻る。
+// 入力待ちのプレイヤーは、新しいマスでも同じ種類の入力が必要ならもう一度入力を求め、そうでなければ入力待ちを取り消して手番を終える。
+func (gm *GameManager) ReloadBoard(board *sugoroku.Board) error {
+	plan, err := gm.PrepareReload(board)
+	if err != nil {
+		return err
+	}
+	return plan.Commit()
+}
+
+// PrepareReload は盤面をこのゲームに反映できるか確かめ、差し替えに使うタイルを作る。
+// ゲームはまだ変えないので、複数の部屋に反映するときはすべての部屋で準備できてからCommitする。
+func (gm *GameManager) PrepareReload(board *sugoroku.Board) (*ReloadPlan, error) {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	if err := gm.checkOpen(); err != nil {
+		return nil, err
+	}
+	tileMap, err := board.TileMap()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tiles: %w", err)
+	}
+	if _, ok := tileMap[sugoroku.InitialTileID]; !ok {
+		return nil, fmt.Errorf("new board has no start tile")
+	}
+	return &ReloadPlan{gm: gm, board: board, tileMap: tileMap}, nil
+}
+
+// Commit は準備した盤面でゲームの盤面を差し替える
+func (plan *ReloadPlan) Commit() error {
+	gm, board := plan.gm, plan.board
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if err := gm.checkOpen(); err != nil {
+		return err
+	}
+	// 再生時に同じ盤面を使えるように、盤面ごと記録する
+	gm.record(journal.KindCommand, CommandBoardReloaded, "", board)
+
+	relocated, err := gm.game.ReplaceTiles(plan.tileMap)
+	if err != nil {
+		return fmt.Errorf("failed to replace tiles: %w", err)
+	}
+	gm.game.SetBoardVersion(board.Version)
+
+	view := gm.game.Board()
+	gm.broadcastBoardUpdated(view.Version, view.Hash, len(board.Tiles), len(board.Quizzes), relocated)
+	for _, playerID := range relocated {
+		gm.broadcastPlayerMoved(playerID, sugoroku.InitialTileID, []int{sugoroku.InitialTileID})
+	}
+	log.WithFields(log.Fields{
+		"tiles":     len(board.Tiles),
+		"quizzes":   len(board.Quizzes),
+		"relocated": relocated,
+	}).Info("Board reloaded")
+
+	for playerID, p := range gm.pending {
+		if err := gm.repromptAfterReload(playerID, p); err != nil {
+			log.WithError(err).WithField("playerID", playerID).Warn("Pending decision cancelled by board reload")
+			gm.clearPending(playerID)
+			gm.endTurn(playerID)
+		}
+	}
+	return nil
+}
+
+// Board はゲームが使っている盤面を返す
+func (gm *GameManager) Board() sugoroku.BoardView {
+	return gm.game.Board()
+}
+
+// repromptAfterReload は差し替えた盤面でも入力要求が続けられるか確かめ、もう一度入力を求める
+func (gm *GameManager) repromptAfterReload(playerID string, p *Pending) error {
+	player, err := gm.game.GetPlayer(playerID)
+	if err != nil {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	if player.Position.Id != p.tileID {
+		return fmt.Errorf("player moved from tile %d", p.tileID)
+	}
+	in, ok := interactionFor(player.Position.Effect)
+	if !ok || in.Kind != p.kind {
+		return fmt.Errorf("tile %d no longer requires %s", p.tileID, p.kind)
+	}
+	event, err := in.Prompt(gm, player, p)
+	if err != nil {
+		return err
+	}
+	return gm.sendPrompt(playerID, event)
+}
@@ -1,14 +1,31 @@
 package game
 
 import (
-	log "github.com/sirupsen/logrus"
-
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
 	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
 )
 
+// broadcast はイベントをジャーナルに記録してから全クライアントに通知する
+func (gm *GameManager) broadcast(event map[string]any) {
+	gm.record(journal.KindEvent, event["type"].(string), "", event["payload"])
+	if gm.hub == nil {
+		return
+	}
+	gm.hub.Broadcast(event)
+}
+
+// sendToPlayer はイベントをジャーナルに記録してから特定のプレイヤーに送信する
+func (gm *GameManager) sendToPlayer(playerID string, event map[string]any) error {
+	gm.record(journal.KindEvent, event["type"].(string), playerID, event["payload"])
+	if gm.hub == nil {
+		return nil
+	}
+	return gm.hub.SendToPlayer(playerID, event)
+}
+
 // broadcastMoneyChanged は所持金変動イベントを全クライアントに通知
 func (gm *GameManager) broadcastMoneyChanged(userID string, newMoney int) {
-	gm.hub.Broadcast(map[string]any{
+	gm.broadcast(map[string]any{
 		"type": "MONEY_CHANGED",
 		"payload": map[string]any{
 			"userID":   userID,
@@ -17,79 +34,113 @@ func (gm *GameManager) broadcastMoneyChanged(userID string, newMoney int) {
 	})
 }
 
-// broadcastPlayerMoved はプレイヤー移動イベントを全クライアントに通知
-func (gm *GameManager) broadcastPlayerMoved(userID string, newPosition int) {
-	gm.hub.Broadcast(map[string]any{
+// broadcastMoneyTransfers は1つの効果で複数のプレイヤーの所持金が変わったときに、変化をまとめて全クライアントに通知
+func (gm *GameManager) broadcastMoneyTransfers(causedBy string, tileID int, transfers []moneyTransfer) {
+	gm.broadcast(map[string]any{
+		"type": "MONEY_TRANSFERS",
+		"payload": map[string]any{
+			"causedBy":  causedBy,
+			"tileID":    tileID,
+			"transfers": transfers,
+		},
+	})
+}
+
+// broadcastPlayerMoved はプレイヤー移動イベントを全クライアントに通知。pathは通ったマスを順に並べたもの
+func (gm *GameManager) broadcastPlayerMoved(userID string, newPosition int, path []int) {
+	gm.broadcast(map[string]any{
 		"type": "PLAYER_MOVED",
 		"payload": map[string]any{
 			"userID":      userID,
 			"newPosition": newPosition,
+			"path":        path,
 		},
 	})
 }
-func (gm *GameManager) sendBranchSelection(player *sugoroku.Player, tile *sugoroku.Tile, effect sugoroku.BranchEffect) error {
-	options := effect.GetOptions(tile)
+
+// sendPrompt は入力要求のイベントに回答期限を付けてプレイヤーに送信する
+func (gm *GameManager) sendPrompt(playerID string, event map[string]any) error {
+	return gm.sendToPlayer(playerID, gm.withDeadline(playerID, event))
+}
+
+func (gm *GameManager) sendDiceRollResult(playerID string, diceResult int) error {
 	event := map[string]any{
-		"type": "BRANCH_CHOICE_REQUIRED",
+		"type": "DICE_RESULT",
 		"payload": map[string]any{
-			"tileID":  tile.Id,
-			"options": options,
+			"userID":     playerID,
+			"diceResult": diceResult,
 		},
 	}
-	return gm.hub.SendToPlayer(player.Id, event)
+	return gm.sendToPlayer(playerID, event)
 }
 
-func (gm *GameManager) sendQuizInfo(player *sugoroku.Player, tile *sugoroku.Tile, effect sugoroku.QuizEffect) error {
-	quizData := effect.GetOptions(tile)
-	event := map[string]any{
-		"type": "QUIZ_REQUIRED",
-		"payload": map[string]any{
-			"tileID":   tile.Id,
-			"quizData": quizData,
-		},
+// broadcastGateResult は関所の判定結果を全クライアントに通知
+func (gm *GameManager) broadcastGateResult(userID string, tileID int, result sugoroku.GateResult) {
+	payload := map[string]any{
+		"userID":       userID,
+		"tileID":       tileID,
+		"mode":         result.Mode,
+		"requireValue": result.RequireValue,
+		"passed":       result.Passed,
+		"paid":         result.Paid,
+		"held":         result.Held,
+	}
+	if result.Mode == sugoroku.GateMinDice {
+		payload["diceResult"] = result.DiceResult
 	}
-	return gm.hub.SendToPlayer(player.Id, event)
+	gm.broadcast(map[string]any{
+		"type":    "GATE_RESULT",
+		"payload": payload,
+	})
 }
 
-func (gm *GameManager) sendGambleRequire(player *sugoroku.Player, tile *sugoroku.Tile) error {
-	baseValue := 3
-	event := map[string]any{
-		"type": "GAMBLE_REQUIRED",
+// broadcastLotteryResult はくじ引きマスの抽選結果を全クライアントに通知
+func (gm *GameManager) broadcastLotteryResult(userID string, tileID int, index int, label string) {
+	gm.broadcast(map[string]any{
+		"type": "LOTTERY_RESULT",
 		"payload": map[string]any{
-			"tileID":         tile.Id,
-			"referenceValue": baseValue,
+			"userID": userID,
+			"tileID": tileID,
+			"index":  index,
+			"label":  label,
 		},
-	}
-	return gm.hub.SendToPlayer(player.Id, event)
+	})
 }
 
-func (gm *GameManager) sendGambleResult(playerID string, payload map[string]any) {
-	event := map[string]any{
-		"type":    "GAMBLE_RESULT",
-		"payload": payload,
-	}
-	if err := gm.hub.SendToPlayer(playerID, event); err != nil {
-		log.WithFields(log.Fields{
-			"error":    err,
-			"playerID": playerID,
-		}).Error("failed to send gamble result to player")
-	}
+// broadcastDecisionTimeout は回答期限が過ぎて既定の回答を適用したことを全クライアントに通知。
+// selectionは分岐や選択マスで代わりに選んだ選択肢(クイズとギャンブルではnil)
+func (gm *GameManager) broadcastDecisionTimeout(userID string, tileID int, kind PendingKind, selection any) {
+	gm.broadcast(map[string]any{
+		"type": "DECISION_TIMEOUT",
+		"payload": map[string]any{
+			"userID":    userID,
+			"tileID":    tileID,
+			"kind":      kind,
+			"selection": selection,
+		},
+	})
 }
 
-func (gm *GameManager) sendDiceRollResult(playerID string, diceResult int) error {
-	event := map[string]any{
-		"type": "DICE_RESULT",
+// broadcastBoardUpdated は盤面が差し替えられたことを全クライアントに通知。relocatedはスタートに戻されたプレイヤー
+func (gm *GameManager) broadcastBoardUpdated(version int, hash string, tileCount, quizCount int, relocated []string) {
+	if relocated == nil {
+		relocated = []string{}
+	}
+	gm.broadcast(map[string]any{
+		"type": "BOARD_UPDATED",
 		"payload": map[string]any{
-			"userID":     playerID,
-			"diceResult": diceResult,
+			"version":   version,
+			"hash":      hash,
+			"tileCount": tileCount,
+			"quizCount": quizCount,
+			"relocated": relocated,
 		},
-	}
-	return gm.hub.SendToPlayer(playerID, event)
+	})
 }
 
 // broadcastPlayerFinished はプレイヤーがゴールしたことを全クライアントに通知
 func (gm *GameManager) broadcastPlayerFinished(userID string, money int) {
-	gm.hub.Broadcast(map[string]any{
+	gm.broadcast(map[string]any{
 		"type": "PLAYER_FINISHED",
 		"payload": map[string]any{
 			"userID": userID,
@@ -100,7 +151,7 @@ func (gm *GameManager) broadcastPlayerFinished(userID string, money int) {
 
 // broadcastPlayerStatusChanged はプレイヤーステータス変更イベントを全クライアントに通知
 func (gm *GameManager) broadcastPlayerStatusChanged(userID string, status string, value any) {
-	gm.hub.Broadcast(map[string]any{
+	gm.broadcast(map[string]any{
 		"type": "PLAYER_STATUS_CHANGED",
 		"payload": map[string]any{
 			"userID": userID,
@@ -109,3 +160,23 @@ func (gm *GameManager) broadcastPlayerStatusChanged(userID string, status string
 		},
 	})
 }
+
+// broadcastTurnStarted は手番の開始を全クライアントに通知
+func (gm *GameManager) broadcastTurnStarted(userID string) {
+	gm.broadcast(map[string]any{
+		"type": "TURN_STARTED",
+		"payload": map[string]any{
+			"userID": userID,
+		},
+	})
+}
+
+// broadcastTurnEnded は手番の終了を全クライアントに通知
+func (gm *GameManager) broadcastTurnEnded(userID string) {
+	gm.broadcast(map[string]any{
+		"type": "TURN_ENDED",
+		"payload": map[string]any{
+			"userID": userID,
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/hub"
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// PendingSnapshot は保存用の未回答の入力要求
+type PendingSnapshot struct {
+	Kind   string `json:"kind"`
+	TileID int    `json:"tileID"`
+	Steps  int    `json:"steps,omitempty"`
+	QuizID int    `json:"quizID,omitempty"`
+
+	Quiz *sugoroku.Quiz `json:"quiz,omitempty"` // 出題したクイズ。再起動前にクイズが差し替えられていても同じ内容で採点する
+}
+
+// Snapshot は再起動後に進行中のゲームを再開するための状態
+type Snapshot struct {
+	Game      sugoroku.Snapshot          `json:"game"`
+	TurnBased bool                       `json:"turnBased"`
+	Pending   map[string]PendingSnapshot `json:"pending,omitempty"`
+}
+
+// Snapshot はゲームと入力待ちの状態を保存用に書き出す
+func (gm *GameManager) Snapshot() Snapshot {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+
+	s := Snapshot{
+		Game:      gm.game.Snapshot(),
+		TurnBased: gm.turnBased,
+		Pending:   make(map[string]PendingSnapshot, len(gm.pending)),
+	}
+	for id, p := range gm.pending {
+		ps := PendingSnapshot{Kind: string(p.kind), TileID: p.tileID, Steps: p.steps}
+		if p.quiz != nil {
+			ps.QuizID = p.quiz.ID
+			ps.Quiz = p.quiz
+		}
+		s.Pending[id] = ps
+	}
+	return s
+}
+
+// Restore は保存した状態でゲームを置き換える。
+// 復元したプレイヤーは未接続として扱われ、再接続するとそのまま続きから遊べる。
+func (gm *GameManager) Restore(s Snapshot) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	gm.record(journal.KindCommand, CommandRestored, "", s)
+
+	if err := gm.game.Restore(s.Game); err != nil {
+		return fmt.Errorf("failed to restore game: %w", err)
+	}
+	gm.turnBased = s.TurnBased
+	gm.playerClients = make(map[string]*hub.Client)
+	for id := range gm.pending {
+		gm.clearPending(id)
+	}
+	// 回答期限は保存しないので、復元した時点から数え直す
+	for id, p := range s.Pending {
+		pending := &Pending{kind: PendingKind(p.Kind), tileID: p.TileID, steps: p.Steps, quiz: p.Quiz}
+		// 出題したクイズの内容を保存していない古いスナップショットは、IDで今のクイズを探す
+		if pending.quiz == nil && p.QuizID != 0 {
+			if quiz, ok := gm.game.FindQuiz(p.QuizID); ok {
+				pending.quiz = quiz
+			}
+		}
+		gm.pending[id] = pending
+		gm.startDeadline(id, pending)
+	}
+
+	log.WithField("players", len(s.Game.Players)).Info("Game restored from snapshot")
+	return nil
+}
+
+// reattachPlayer は復元されたプレイヤーに新しい接続を割り当てる。
+// 回答待ちの入力があれば、もう一度入力を求める。
+func (gm *GameManager) reattachPlayer(player *sugoroku.Player, c *hub.Client) error {
+	gm.playerClients[player.Id] = c
+	log.WithField("playerID", player.Id).Info("Player reattached")
+
+	p, ok := gm.pending[player.Id]
+	if !ok || p.tileID != player.Position.Id {
+		return nil
+	}
+
+	in, ok := interactionsByKind[p.kind]
+	if !ok {
+		return nil
+	}
+	event, err := in.Prompt(gm, player, p)
+	if err != nil {
+		log.WithError(err).WithField("playerID", player.Id).Warn("failed to resend pending decision")
+		return nil
+	}
+
+	// Hubへの登録を待たずに、接続へ直接送る
+	gm.withDeadline(player.Id, event)
+	gm.record(journal.KindEvent, event["type"].(string), player.Id, event["payload"])
+	if c == nil {
+		return nil
+	}
+	return c.SendJSON(event)
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameManager_SnapshotRestoreReattach(t *testing.T) {
+	tilePath := getTestFilePath(t, "test/test_tiles.json")
+	gm, h := setupTestEnvironment(t, tilePath)
+	createAndRegisterClient(t, gm, h, "player1")
+
+	// クイズマスで回答待ちのまま保存する
+	assert.NoError(t, gm.MoveByDiceRoll("player1", 2))
+	snapshot := gm.Snapshot()
+	assert.Equal(t, string(pendingQuiz), snapshot.Pending["player1"].Kind)
+	assert.Equal(t, 1, snapshot.Pending["player1"].QuizID)
+	if assert.NotNil(t, snapshot.Pending["player1"].Quiz) {
+		assert.Equal(t, "1 + 1は？", snapshot.Pending["player1"].Quiz.Question)
+	}
+
+	// 再起動後の新しいGameManagerに復元する
+	restored, restoredHub := setupTestEnvironment(t, tilePath)
+	assert.NoError(t, restored.Restore(snapshot))
+
+	player, err := restored.game.GetPlayer("player1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, player.Position.Id)
+
+	// 再接続すると最初からやり直さず、回答待ちのクイズをもう一度求められる
+	client := createAndRegisterClient(t, restored, restoredHub, "player1")
+	assertEventReceived(t, client, "QUIZ_REQUIRED")
+	assert.Equal(t, 3, player.Position.Id)
+
+	assert.NoError(t, restored.HandleQuiz("player1", map[string]any{"quizID": float64(1), "selection": float64(1)}))
+	_, stillPending := restored.pending["player1"]
+	assert.False(t, stillPending)
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// playerStatus は通知済みのプレイヤーの状態
+type playerStatus struct {
+	position    int
+	money       int
+	isMarried   bool
+	hasChildren int
+	job         string
+}
+
+func currentStatus(p *sugoroku.Player) playerStatus {
+	return playerStatus{
+		position:    p.Position.Id,
+		money:       p.Money,
+		isMarried:   p.IsMarried,
+		hasChildren: p.HasChildren,
+		job:         p.Job,
+	}
+}
+
+// moneyTransfer は1人のプレイヤーの所持金の変化
+type moneyTransfer struct {
+	UserID   string `json:"userID"`
+	Delta    int    `json:"delta"`
+	NewMoney int    `json:"newMoney"`
+}
+
+// statusReporter は前回の通知からのプレイヤーの変化を全クライアントに通知する。
+// 効果を1つ適用するごとにreportを呼ぶと、効果ごとの変化として通知される。
+// 所持金は効果を受けたプレイヤー以外の分も追跡する。
+type statusReporter struct {
+	gm     *GameManager
+	player *sugoroku.Player
+	last   playerStatus
+	money  map[string]int // 通知済みの全プレイヤーの所持金
+}
+
+func (gm *GameManager) newStatusReporter(player *sugoroku.Player) *statusReporter {
+	player.TakePath() // 以前の移動の記録は通知しない
+	return &statusReporter{gm: gm, player: player, last: currentStatus(player), money: gm.allMoney()}
+}
+
+func (gm *GameManager) allMoney() map[string]int {
+	money := make(map[string]int)
+	for _, p := range gm.game.GetAllPlayers() {
+		money[p.Id] = p.Money
+	}
+	return money
+}
+
+// moved は前回の通知から位置が変わったかどうかを返す
+func (r *statusReporter) moved() bool {
+	return r.player.Position.Id != r.last.position
+}
+
+// report は前回の通知から変化した項目を通知する
+func (r *statusReporter) report() {
+	now := currentStatus(r.player)
+	id := r.player.Id
+
+	if path := r.player.TakePath(); now.position != r.last.position || len(path) > 0 {
+		if len(path) == 0 {
+			path = []int{now.position}
+		}
+		r.gm.broadcastPlayerMoved(id, now.position, path)
+	}
+	r.reportMoney()
+	if now.isMarried != r.last.isMarried {
+		r.gm.broadcastPlayerStatusChanged(id, "isMarried", now.isMarried)
+	}
+	if now.hasChildren != r.last.hasChildren {
+		r.gm.broadcastPlayerStatusChanged(id, "hasChildren", now.hasChildren)
+	}
+	if now.job != r.last.job {
+		r.gm.broadcastPlayerStatusChanged(id, "job", now.job)
+	}
+	r.last = now
+}
+
+// reportMoney は所持金が変化したすべてのプレイヤーのMONEY_CHANGEDを通知する。
+// ほかのプレイヤーの所持金も変化した場合は、やり取り全体をMONEY_TRANSFERSでも通知する。
+func (r *statusReporter) reportMoney() {
+	now := r.gm.allMoney()
+
+	// 効果を受けたプレイヤーを先頭にし、ほかのプレイヤーはID順に並べる(リプレイで順番が変わらないように)
+	var others []string
+	for id, after := range now {
+		if before, ok := r.money[id]; ok && before != after && id != r.player.Id {
+			others = append(others, id)
+		}
+	}
+	sort.Strings(others)
+	changed := others
+	if before, ok := r.money[r.player.Id]; ok && before != now[r.player.Id] {
+		changed = append([]string{r.player.Id}, others...)
+	}
+
+	transfers := make([]moneyTransfer, 0, len(changed))
+	for _, id := range changed {
+		transfers = append(transfers, moneyTransfer{UserID: id, Delta: now[id] - r.money[id], NewMoney: now[id]})
+	}
+	for _, t := range transfers {
+		r.gm.broadcastMoneyChanged(t.UserID, t.NewMoney)
+	}
+	if len(others) > 0 {
+		r.gm.broadcastMoneyTransfers(r.player.Id, r.player.Position.Id, transfers)
+	}
+	r.money = now
+	r.last.money = now[r.player.Id]
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+)
+
+// DecisionTimeouts は入力要求の種類ごとの回答期限。指定がないか0の場合は期限なし。
+// 種類はRegisterInteractionで登録したものが使える。
+type DecisionTimeouts map[PendingKind]time.Duration
+
+// PendingKinds はRegisterInteractionで登録された入力要求の種類を名前順に返す
+func PendingKinds() []PendingKind {
+	kinds := make([]PendingKind, 0, len(interactionsByKind))
+	for kind := range interactionsByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+// IsPendingKind は登録された入力要求の種類かどうかを返す
+func IsPendingKind(kind PendingKind) bool {
+	_, ok := interactionsByKind[kind]
+	return ok
+}
+
+// SetDecisionTimeouts は入力要求の回答期限を設定する。以降に出す入力要求から有効になる。
+func (gm *GameManager) SetDecisionTimeouts(t DecisionTimeouts) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	gm.timeouts = make(DecisionTimeouts, len(t))
+	for kind, d := range t {
+		gm.timeouts[kind] = d
+	}
+}
+
+// startDeadline は入力要求の回答期限を決め、期限が来たら既定の回答を適用するタイマーを開始する
+func (gm *GameManager) startDeadline(playerID string, p *Pending) {
+	d := gm.timeouts[p.kind]
+	if d <= 0 {
+		return
+	}
+	p.deadline = time.Now().Add(d)
+	p.timer = time.AfterFunc(d, func() {
+		gm.mu.Lock()
+		defer gm.mu.Unlock()
+		// 期限までに回答された入力要求や、閉じた部屋の入力要求は処理しない
+		if gm.closed || gm.pending[playerID] != p {
+			return
+		}
+		if err := gm.expireDecisionLocked(playerID); err != nil {
+			log.WithError(err).WithField("playerID", playerID).Error("failed to apply default decision")
+		}
+	})
+}
+
+// withDeadline は回答期限があれば入力要求のイベントのpayloadにdeadlineを加える
+func (gm *GameManager) withDeadline(playerID string, event map[string]any) map[string]any {
+	p, ok := gm.pending[playerID]
+	if !ok || p.deadline.IsZero() {
+		return event
+	}
+	if payload, ok := event["payload"].(map[string]any); ok {
+		payload["deadline"] = p.deadline
+	}
+	return event
+}
+
+// ExpireDecision はプレイヤーの入力要求を期限切れとして、既定の回答を適用する。
+// 分岐と選択マスは最初の選択肢、クイズは不正解、ギャンブルは賭けなしとして扱う。
+func (gm *GameManager) ExpireDecision(playerID string) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	return gm.expireDecisionLocked(playerID)
+}
+
+func (gm *GameManager) expireDecisionLocked(playerID string) error {
+	if err := gm.checkOpen(); err != nil {
+		return err
+	}
+	gm.record(journal.KindCommand, CommandDecisionTimeout, playerID, nil)
+	p, ok := gm.pending[playerID]
+	if !ok {
+		return ErrUnexpectedSubmit
+	}
+	player, err := gm.game.GetPlayer(playerID)
+	if err != nil {
+		return fmt.Errorf("player %s not found", playerID)
+	}
+	tile := player.Position
+	log.WithFields(log.Fields{
+		"playerID": playerID,
+		"tileID":   tile.Id,
+		"kind":     p.kind,
+	}).Info("Decision timed out")
+
+	in, ok := interactionsByKind[p.kind]
+	if !ok {
+		return fmt.Errorf("unknown pending kind %s", p.kind)
+	}
+	var selection any
+	if in.Default != nil {
+		if selection, err = in.Default(gm, player, p); err != nil {
+			return err
+		}
+	}
+	// 既定の回答による変化より先に、期限切れを通知する
+	gm.broadcastDecisionTimeout(player.Id, tile.Id, p.kind, selection)
+	return gm.resolvePending(player, func(p *Pending) error {
+		if in.Expire == nil {
+			return nil
+		}
+		return in.Expire(gm, player, p, selection)
+	})
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// SetTurnBased は手番制を有効または無効にする。
+// 有効な場合、ROLL_DICEは手番のプレイヤーからしか受け付けない。
+func (gm *GameManager) SetTurnBased(enabled bool) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	gm.turnBased = enabled
+}
+
+// IsTurnBased は手番制が有効かどうかを返す
+func (gm *GameManager) IsTurnBased() bool {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.turnBased
+}
+
+// CurrentTurn は現在手番のプレイヤーIDを返す。手番制でない場合は空文字を返す。
+func (gm *GameManager) CurrentTurn() string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	if !gm.turnBased {
+		return ""
+	}
+	return gm.game.CurrentTurn()
+}
+
+// checkTurn は手番制の場合に、プレイヤーが手番かどうかを確認する
+func (gm *GameManager) checkTurn(playerID string) error {
+	if !gm.turnBased {
+		return nil
+	}
+	if gm.game.CurrentTurn() != playerID {
+		return ErrNotYourTurn
+	}
+	return nil
+}
+
+// endTurn はマスの効果の解決が終わったプレイヤーの手番を終了し、次のプレイヤーに手番を渡す
+func (gm *GameManager) endTurn(playerID string) {
+	if !gm.turnBased || gm.game.CurrentTurn() != playerID {
+		return
+	}
+	gm.broadcastTurnEnded(playerID)
+	next := gm.game.AdvanceTurn()
+	log.WithFields(log.Fields{"playerID": playerID, "next": next}).Info("Turn advanced")
+	gm.broadcastTurnStarted(next)
+}
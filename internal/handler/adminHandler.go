@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/boardstore"
+	"github.com/shii-park/Metasugo-Backend/internal/room"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// AdminHandler は運営者向けの操作を扱う
+type AdminHandler struct {
+	rooms  *room.Registry
+	boards *boardstore.Store
+}
+
+// tileRequest はマスを追加・変更するときのリクエスト。messageは版の説明として保存される
+type tileRequest struct {
+	sugoroku.TileJSON
+	Message string `json:"message"`
+}
+
+type effectRequest struct {
+	Effect  json.RawMessage `json:"effect"`
+	Message string          `json:"message"`
+}
+
+type activeRequest struct {
+	Version int `json:"version"`
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(rooms *room.Registry, boards *boardstore.Store) *AdminHandler {
+	return &AdminHandler{rooms: rooms, boards: boards}
+}
+
+// ReloadBoard は盤面とクイズのファイルを読み直して新しい版として保存し、すべての部屋に反映する
+func (h *AdminHandler) ReloadBoard(c *gin.Context) {
+	result, err := h.rooms.ReloadBoard(c.GetString("firebase_uid"))
+	var validationErr *sugoroku.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "盤面に問題があるため反映しませんでした", "issues": validationErr.Issues})
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("failed to reload board")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "盤面の読み直しに失敗しました"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListVersions は保存されている盤面の版の一覧を返す
+func (h *AdminHandler) ListVersions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.boards.List())
+}
+
+// GetVersion は指定した版の盤面を返す
+func (h *AdminHandler) GetVersion(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版の番号が不正です"})
+		return
+	}
+	v, err := h.boards.Get(number)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// ListTiles は最新の版のマスの一覧を返す
+func (h *AdminHandler) ListTiles(c *gin.Context) {
+	latest := h.boards.Latest()
+	c.JSON(http.StatusOK, gin.H{"version": latest.Number, "tiles": latest.Tiles})
+}
+
+// GetTile は最新の版のマスを1つ返す
+func (h *AdminHandler) GetTile(c *gin.Context) {
+	id, ok := tileIDParam(c)
+	if !ok {
+		return
+	}
+	for _, tile := range h.boards.Latest().Tiles {
+		if tile.ID == id {
+			c.JSON(http.StatusOK, tile)
+			return
+		}
+	}
+	h.respondError(c, boardstore.ErrTileNotFound)
+}
+
+// CreateTile はマスを追加した新しい版を保存する
+func (h *AdminHandler) CreateTile(c *gin.Context) {
+	var req tileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "リクエストの形式が不正です"})
+		return
+	}
+	h.edit(c, http.StatusCreated, req.Message, func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return boardstore.AddTile(tiles, req.TileJSON)
+	})
+}
+
+// UpdateTile はマスを置き換えた新しい版を保存する
+func (h *AdminHandler) UpdateTile(c *gin.Context) {
+	id, ok := tileIDParam(c)
+	if !ok {
+		return
+	}
+	var req tileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "リクエストの形式が不正です"})
+		return
+	}
+	h.edit(c, http.StatusOK, req.Message, func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return boardstore.ReplaceTile(tiles, id, req.TileJSON)
+	})
+}
+
+// UpdateEffect はマスの効果だけを置き換えた新しい版を保存する
+func (h *AdminHandler) UpdateEffect(c *gin.Context) {
+	id, ok := tileIDParam(c)
+	if !ok {
+		return
+	}
+	var req effectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "リクエストの形式が不正です"})
+		return
+	}
+	h.edit(c, http.StatusOK, req.Message, func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return boardstore.SetEffect(tiles, id, req.Effect)
+	})
+}
+
+// DeleteTile はマスを削除した新しい版を保存する
+func (h *AdminHandler) DeleteTile(c *gin.Context) {
+	id, ok := tileIDParam(c)
+	if !ok {
+		return
+	}
+	h.edit(c, http.StatusOK, c.Query("message"), func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		return boardstore.DeleteTile(tiles, id)
+	})
+}
+
+// RollbackVersion は指定した版の盤面を最新の版として保存し直す
+func (h *AdminHandler) RollbackVersion(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版の番号が不正です"})
+		return
+	}
+	v, err := h.boards.Rollback(number, c.GetString("firebase_uid"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, v)
+}
+
+// SetActiveVersion は新しく作る部屋で使う版を変更する
+func (h *AdminHandler) SetActiveVersion(c *gin.Context) {
+	var req activeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "リクエストの形式が不正です"})
+		return
+	}
+	if err := h.boards.SetActive(req.Version); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"version": req.Version})
+}
+
+// edit は最新の版を編集して新しい版を保存し、保存した版と検証の警告を返す
+func (h *AdminHandler) edit(c *gin.Context, status int, message string, edit func([]sugoroku.TileJSON) ([]sugoroku.TileJSON, error)) {
+	v, issues, err := h.boards.Edit(c.GetString("firebase_uid"), message, edit)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if issues == nil {
+		issues = []sugoroku.Issue{}
+	}
+	c.JSON(status, gin.H{"version": v.Number, "warnings": issues})
+}
+
+// respondError は盤面の保存で起きたエラーを対応するステータスコードで返す
+func (h *AdminHandler) respondError(c *gin.Context, err error) {
+	var validationErr *sugoroku.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "盤面に問題があるため保存しませんでした", "issues": validationErr.Issues})
+	case errors.Is(err, boardstore.ErrVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "版が見つかりません"})
+	case errors.Is(err, boardstore.ErrTileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "マスが見つかりません"})
+	case errors.Is(err, boardstore.ErrTileExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "同じIDのマスが既に存在します"})
+	default:
+		log.WithError(err).Error("failed to save board")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "盤面の保存に失敗しました"})
+	}
+}
+
+func tileIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("tileID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "マスIDが不正です"})
+		return 0, false
+	}
+	return id, true
+}
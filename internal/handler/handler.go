@@ -4,22 +4,15 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/gin-gonic/gin"
-	"github.com/shii-park/Metasugo-Backend/internal/game"
-	"github.com/shii-park/Metasugo-Backend/internal/hub"
+	"github.com/shii-park/Metasugo-Backend/internal/boardstore"
 	"github.com/shii-park/Metasugo-Backend/internal/middleware"
-	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+	"github.com/shii-park/Metasugo-Backend/internal/room"
 )
 
-func SetupRoutes(router *gin.Engine, sg *sugoroku.Game) {
-	// Hubの初期化
-	hub := hub.NewHub()
-	go hub.Run()
-
-	// GameManagerの初期化
-	gm := game.NewGameManager(sg, hub)
-
-	// WebSocketHandlerの初期化
-	wsHandler := NewWebSocketHandler(hub)
+func SetupRoutes(router *gin.Engine, rooms *room.Registry, boards *boardstore.Store) {
+	// RoomHandlerの初期化
+	roomHandler := NewRoomHandler(rooms)
+	adminHandler := NewAdminHandler(rooms, boards)
 
 	// RankingHandlerの初期化
 	rankingHandler, err := NewRankingHandler()
@@ -45,13 +38,36 @@ func SetupRoutes(router *gin.Engine, sg *sugoroku.Game) {
 	authRequired := router.Group("/")
 	authRequired.Use(middleware.AuthToken())
 	{
-		// WebSocketのルーティング
-		authRequired.GET("/ws", wsHandler.HandleWebSocket(gm))
+		// WebSocketのルーティング(部屋IDを省略した場合はデフォルトの部屋)
+		authRequired.GET("/ws", roomHandler.HandleWebSocket)
+		authRequired.GET("/ws/:roomID", roomHandler.HandleWebSocket)
+		// 部屋のルーティング
+		authRequired.GET("/rooms", roomHandler.ListRooms)
+		authRequired.POST("/rooms", roomHandler.CreateRoom)
+		authRequired.GET("/rooms/:roomID", roomHandler.GetRoom)
+		authRequired.DELETE("/rooms/:roomID", roomHandler.CloseRoom)
+		authRequired.GET("/rooms/:roomID/board", roomHandler.GetBoard)
+		authRequired.GET("/rooms/:roomID/board/graph", roomHandler.GetBoardGraph)
 		// ランキングのルーティング
 		authRequired.GET("/ranking", rankingHandler.GetRanking)
-		// タイルのルーティング
-		authRequired.GET("/tiles", TilesHandler)
+		// 盤面のルーティング(部屋IDを省略した場合はデフォルトの部屋)
+		authRequired.GET("/board", roomHandler.GetBoard)
 		//最高金額取得のルーティング
 		authRequired.GET("/bestscore", bestScoreHandler.GetBestScore)
+		// 運営者向けのルーティング
+		admin := authRequired.Group("/admin")
+		admin.Use(middleware.RequireAdmin())
+		admin.POST("/board/reload", adminHandler.ReloadBoard)
+		// 盤面の編集(変更のたびに新しい版として保存する)
+		admin.GET("/board/versions", adminHandler.ListVersions)
+		admin.GET("/board/versions/:version", adminHandler.GetVersion)
+		admin.POST("/board/versions/:version/rollback", adminHandler.RollbackVersion)
+		admin.PUT("/board/active", adminHandler.SetActiveVersion)
+		admin.GET("/board/tiles", adminHandler.ListTiles)
+		admin.POST("/board/tiles", adminHandler.CreateTile)
+		admin.GET("/board/tiles/:tileID", adminHandler.GetTile)
+		admin.PUT("/board/tiles/:tileID", adminHandler.UpdateTile)
+		admin.PUT("/board/tiles/:tileID/effect", adminHandler.UpdateEffect)
+		admin.DELETE("/board/tiles/:tileID", adminHandler.DeleteTile)
 	}
 }
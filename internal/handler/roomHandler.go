@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shii-park/Metasugo-Backend/internal/board"
+	"github.com/shii-park/Metasugo-Backend/internal/room"
+)
+
+// RoomHandler は部屋の作成・一覧・参加・終了を扱う
+type RoomHandler struct {
+	rooms *room.Registry
+}
+
+type createRoomRequest struct {
+	ID string `json:"id"`
+	room.Options
+}
+
+// NewRoomHandler creates a new RoomHandler.
+func NewRoomHandler(rooms *room.Registry) *RoomHandler {
+	return &RoomHandler{rooms: rooms}
+}
+
+// ListRooms は稼働中の部屋の一覧を返す
+func (h *RoomHandler) ListRooms(c *gin.Context) {
+	rooms := h.rooms.List()
+	summaries := make([]room.RoomSummary, 0, len(rooms))
+	for _, r := range rooms {
+		summaries = append(summaries, r.Summary())
+	}
+	c.JSON(http.StatusOK, summaries)
+}
+
+// GetRoom は指定した部屋の概要を返す
+func (h *RoomHandler) GetRoom(c *gin.Context) {
+	r, err := h.rooms.Get(c.Param("roomID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "部屋が見つかりません"})
+		return
+	}
+	c.JSON(http.StatusOK, r.Summary())
+}
+
+// CreateRoom は新しい部屋を作成する。IDを省略した場合は自動で採番する。
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	var req createRoomRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "リクエストの形式が不正です"})
+			return
+		}
+	}
+
+	r, err := h.rooms.Create(req.ID, req.Options)
+	if err != nil {
+		if errors.Is(err, room.ErrRoomExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "同じIDの部屋が既に存在します"})
+			return
+		}
+		if errors.Is(err, room.ErrInvalidID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "部屋IDは英数字・ハイフン・アンダースコアで指定してください"})
+			return
+		}
+		if errors.Is(err, room.ErrInvalidDice) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "サイコロの設定が不正です"})
+			return
+		}
+		if errors.Is(err, room.ErrInvalidTimeouts) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "回答期限の設定が不正です"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "部屋の作成に失敗しました"})
+		return
+	}
+	c.JSON(http.StatusCreated, r.Summary())
+}
+
+// CloseRoom は部屋を閉じ、参加中のクライアントを切断する
+func (h *RoomHandler) CloseRoom(c *gin.Context) {
+	if err := h.rooms.Close(c.Param("roomID")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "部屋が見つかりません"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetBoard は部屋のゲームが使っている盤面を返す。部屋IDが指定されていない場合はデフォルトの部屋の盤面を返す。
+// 盤面の内容のハッシュをETagとして返し、If-None-Matchが一致する場合は304を返す。
+func (h *RoomHandler) GetBoard(c *gin.Context) {
+	roomID := c.Param("roomID")
+	if roomID == "" {
+		roomID = room.DefaultRoomID
+	}
+
+	r, err := h.rooms.Get(roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "部屋が見つかりません"})
+		return
+	}
+
+	view := r.Game.Board()
+	etag := `"` + view.Hash + `"`
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// etagMatches はIf-None-Matchヘッダーのいずれかの値がetagと一致するか調べる(弱い比較)
+func etagMatches(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBoardGraph は部屋の盤面をDOT形式またはMermaid形式で返す
+func (h *RoomHandler) GetBoardGraph(c *gin.Context) {
+	r, err := h.rooms.Get(c.Param("roomID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "部屋が見つかりません"})
+		return
+	}
+
+	format := c.DefaultQuery("format", board.FormatDOT)
+	graph, err := board.Render(format, r.Game.Tiles())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "formatには dot または mermaid を指定してください"})
+		return
+	}
+
+	contentType := "text/vnd.graphviz; charset=utf-8"
+	if format == board.FormatMermaid {
+		contentType = "text/plain; charset=utf-8"
+	}
+	c.Data(http.StatusOK, contentType, []byte(graph))
+}
+
+// HandleWebSocket は部屋IDで指定された部屋にWebSocketで参加させる。
+// 部屋IDが指定されていない場合はデフォルトの部屋に参加する。
+func (h *RoomHandler) HandleWebSocket(c *gin.Context) {
+	roomID := c.Param("roomID")
+	if roomID == "" {
+		roomID = room.DefaultRoomID
+	}
+
+	r, err := h.rooms.Get(roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "部屋が見つかりません"})
+		return
+	}
+
+	NewWebSocketHandler(r.Hub).HandleWebSocket(r.Manager)(c)
+}
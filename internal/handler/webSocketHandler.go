@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +11,6 @@ import (
 
 	"github.com/shii-park/Metasugo-Backend/internal/game"
 	"github.com/shii-park/Metasugo-Backend/internal/hub"
-	"github.com/shii-park/Metasugo-Backend/internal/service"
 )
 
 //ハンドラを分割予定
@@ -68,6 +68,11 @@ func (h *WebSocketHandler) HandleWebSocket(gm *game.GameManager) gin.HandlerFunc
 			}).Error("Failed to send all player statuses")
 		}
 
+		// 手番制の場合は現在の手番を通知
+		if current := gm.CurrentTurn(); current != "" {
+			_ = client.SendJSON(gin.H{"type": "TURN_STARTED", "payload": gin.H{"userID": current}})
+		}
+
 		go client.WritePump()
 		go client.ReadPump()
 
@@ -76,15 +81,6 @@ func (h *WebSocketHandler) HandleWebSocket(gm *game.GameManager) gin.HandlerFunc
 	}
 }
 
-func (h *WebSocketHandler) HandleGetTile(client *hub.Client, request map[string]any) {
-	tile, err := service.GetTiles()
-	if err != nil {
-		_ = client.SendJSON(gin.H{"type": "error", "message": "タイルの取得に失敗しました"})
-		return
-	}
-	_ = client.SendJSON(gin.H{"type": "tile", "data": tile})
-}
-
 func (h *WebSocketHandler) processMessage(gm *game.GameManager, client *hub.Client, userID string) {
 	for message := range client.Receive {
 		var req wsRequest
@@ -103,22 +99,22 @@ func (h *WebSocketHandler) processMessage(gm *game.GameManager, client *hub.Clie
 			"request": req.Type,
 		})
 
-		switch req.Type {
-		case "ROLL_DICE":
+		switch {
+		case req.Type == "ROLL_DICE":
 			if err := gm.HandleMove(userID); err != nil {
 				logCtx.WithField("error", err).Error("Error during HandleMove")
+				sendGameError(client, err)
 			}
-		case "SUBMIT_CHOICE":
-			if err := gm.HandleBranch(userID, req.Payload); err != nil {
-				logCtx.WithField("error", err).Error("Error during HandleBranch")
+		case req.Type == "GET_BOARD":
+			// ゲームが使っている盤面を返す
+			if err := client.SendJSON(gin.H{"type": "BOARD", "payload": gm.Board()}); err != nil {
+				logCtx.WithField("error", err).Error("Failed to send board")
 			}
-		case "SUBMIT_GAMBLE":
-			if err := gm.HandleGamble(userID, req.Payload); err != nil {
-				logCtx.WithField("error", err).Error("Error during HandleGamble")
-			}
-		case "SUBMIT_QUIZ":
-			if err := gm.HandleQuiz(userID, req.Payload); err != nil {
-				logCtx.WithField("error", err).Error("Error during HandleQuiz")
+		case game.IsSubmitCommand(req.Type):
+			// SUBMIT_CHOICEなど、マスの入力要求への回答
+			if err := gm.HandleSubmit(req.Type, userID, req.Payload); err != nil {
+				logCtx.WithField("error", err).Error("Error during HandleSubmit")
+				sendGameError(client, err)
 			}
 		default:
 			logCtx.Warn("Unknown request type")
@@ -128,3 +124,14 @@ func (h *WebSocketHandler) processMessage(gm *game.GameManager, client *hub.Clie
 		}
 	}
 }
+
+// sendGameError はゲーム進行上のエラーであれば、コード付きでクライアントに返す
+func sendGameError(client *hub.Client, err error) {
+	var gameErr *game.GameError
+	if !errors.As(err, &gameErr) {
+		return
+	}
+	_ = client.SendJSON(gin.H{
+		"type": "error", "code": gameErr.Code, "message": gameErr.Message,
+	})
+}
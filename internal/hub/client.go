@@ -15,6 +15,8 @@ type Client struct {
 	Send     chan []byte
 	Receive  chan []byte
 	PlayerID string
+
+	closed bool // Sendを閉じた後はtrue。Hubのmuで守る
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, playerID string) *Client {
@@ -35,7 +37,7 @@ const (
 
 func (c *Client) ReadPump() {
 	defer func() {
-		c.Hub.unregister <- c
+		c.Hub.Unregister(c)
 		close(c.Receive)
 		c.Conn.Close()
 	}()
@@ -102,6 +104,9 @@ func (c *Client) SendJSON(v any) error {
 	if err != nil {
 		return err
 	}
+	if c.Hub != nil {
+		return c.Hub.send(c, b)
+	}
 	select {
 	case c.Send <- b:
 		return nil
@@ -18,6 +18,8 @@ type Hub struct {
 	broadcast  chan []byte
 	register   chan *Client
 	unregister chan *Client
+	quit       chan struct{}
+	stopOnce   sync.Once
 
 	mu sync.RWMutex
 }
@@ -29,6 +31,7 @@ func NewHub() *Hub {
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[string]*Client),
+		quit:       make(chan struct{}),
 	}
 }
 
@@ -52,13 +55,7 @@ func (h *Hub) Run() {
 			log.WithField("playerID", client.PlayerID).Info("Client registered")
 
 		case client := <-h.unregister:
-			h.mu.Lock()
-			if c, ok := h.clients[client.PlayerID]; ok && c == client {
-				delete(h.clients, client.PlayerID)
-				close(client.Send)
-				log.WithField("playerID", client.PlayerID).Info("Client unregistered")
-			}
-			h.mu.Unlock()
+			h.removeClient(client)
 
 		case message := <-h.broadcast:
 			h.mu.RLock()
@@ -73,20 +70,75 @@ func (h *Hub) Run() {
 			h.mu.RUnlock()
 
 			for _, client := range clientsToUnregister {
-				h.unregister <- client
+				h.removeClient(client)
 			}
+
+		case <-h.quit:
+			h.mu.Lock()
+			for playerID, client := range h.clients {
+				delete(h.clients, playerID)
+				h.closeClient(client)
+			}
+			h.mu.Unlock()
+			log.Info("Hub stopped")
+			return
 		}
 	}
 }
 
+// removeClient はクライアントを登録解除し、送信チャネルを閉じる
+func (h *Hub) removeClient(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c, ok := h.clients[client.PlayerID]; ok && c == client {
+		delete(h.clients, client.PlayerID)
+		h.closeClient(client)
+		log.WithField("playerID", client.PlayerID).Info("Client unregistered")
+	}
+}
+
+// closeClient はクライアントの送信チャネルを閉じる。h.muのLockを取って呼ぶ
+func (h *Hub) closeClient(client *Client) {
+	client.closed = true
+	close(client.Send)
+}
+
+// send はクライアントにメッセージを送る。送信チャネルを閉じている間に送らないように、h.muのRLockを取ったまま送る
+func (h *Hub) send(client *Client, message []byte) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if client.closed {
+		return fmt.Errorf("client %s is closed", client.PlayerID)
+	}
+	select {
+	case client.Send <- message:
+		return nil
+	default:
+		return fmt.Errorf("client %s send channel is full, message dropped", client.PlayerID)
+	}
+}
+
+// Hubを停止し、接続中のすべてのクライアントの送信チャネルを閉じる
+func (h *Hub) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.quit)
+	})
+}
+
 // Hubに新たなプレイヤーを登録する
 func (h *Hub) Register(client *Client) {
-	h.register <- client
+	select {
+	case h.register <- client:
+	case <-h.quit:
+	}
 }
 
 // Hubからプレイヤーを削除する
 func (h *Hub) Unregister(client *Client) {
-	h.unregister <- client
+	select {
+	case h.unregister <- client:
+	case <-h.quit:
+	}
 }
 
 func (h *Hub) Broadcast(message any) {
@@ -95,7 +147,10 @@ func (h *Hub) Broadcast(message any) {
 		log.WithError(err).Error("could not marshal broadcast message")
 		return
 	}
-	h.broadcast <- rawMessage
+	select {
+	case h.broadcast <- rawMessage:
+	case <-h.quit:
+	}
 }
 
 // 特定のプレイヤーにJSONメッセージを送信する
@@ -105,10 +160,10 @@ func (h *Hub) SendToPlayer(playerID string, message any) error {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
+	// 送り終えるまでRLockを取り、Stopやクライアントの登録解除で送信チャネルが閉じられないようにする
 	h.mu.RLock()
+	defer h.mu.RUnlock()
 	client, ok := h.clients[playerID]
-	h.mu.RUnlock()
-
 	if !ok {
 		return fmt.Errorf("client with playerID %s not found", playerID)
 	}
@@ -79,3 +79,49 @@ func TestHub_SendToPlayer(t *testing.T) {
 		assert.Error(t, err, "Should return an error when player is not found")
 	})
 }
+
+func TestHub_Stop(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	client := NewClient(hub, nil, "player1")
+	hub.Register(client)
+	time.Sleep(50 * time.Millisecond)
+
+	hub.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	// 停止後は送信チャネルが閉じられている
+	_, ok := <-client.Send
+	assert.False(t, ok, "Send channel should be closed after Stop")
+
+	// 停止後の操作はブロックしない
+	hub.Broadcast(map[string]string{"data": "ignored"})
+	hub.Unregister(client)
+	hub.Stop()
+}
+
+func TestHub_SendAfterStop(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	client := NewClient(hub, nil, "player1")
+	hub.Register(client)
+	time.Sleep(50 * time.Millisecond)
+
+	// 停止と同時に送っても、閉じた送信チャネルには送らない
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = hub.SendToPlayer("player1", map[string]int{"i": i})
+			_ = client.SendJSON(map[string]int{"i": i})
+		}
+	}()
+	hub.Stop()
+	<-done
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Error(t, hub.SendToPlayer("player1", map[string]string{"data": "ignored"}))
+	assert.Error(t, client.SendJSON(map[string]string{"data": "ignored"}))
+}
@@ -0,0 +1,158 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Kind はジャーナルに記録されたエントリの種類
+type Kind string
+
+const (
+	KindCommand Kind = "command" // プレイヤーやサーバーからゲームへの操作
+	KindEvent   Kind = "event"   // 操作の結果としてクライアントへ送られたイベント
+)
+
+// Entry はジャーナルの1行
+type Entry struct {
+	Seq      int64           `json:"seq"`
+	Time     time.Time       `json:"time"`
+	Kind     Kind            `json:"kind"`
+	Type     string          `json:"type"`
+	PlayerID string          `json:"playerID,omitempty"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+}
+
+// Journal は追記専用のゲーム記録
+type Journal interface {
+	Append(kind Kind, entryType string, playerID string, payload any) error
+	Close() error
+}
+
+// newEntry は連番と時刻を付けてエントリを作る
+func newEntry(seq int64, kind Kind, entryType string, playerID string, payload any) (Entry, error) {
+	entry := Entry{
+		Seq:      seq,
+		Time:     time.Now(),
+		Kind:     kind,
+		Type:     entryType,
+		PlayerID: playerID,
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return Entry{}, fmt.Errorf("failed to marshal journal payload: %w", err)
+		}
+		entry.Payload = raw
+	}
+	return entry, nil
+}
+
+// FileJournal はJSON Lines形式でファイルに追記するジャーナル
+type FileJournal struct {
+	file *os.File
+	seq  int64
+	mu   sync.Mutex
+}
+
+// OpenFile はジャーナルファイルを追記モードで開く。既存のファイルの場合は連番を引き継ぐ。
+func OpenFile(path string) (*FileJournal, error) {
+	var seq int64
+	if existing, err := ReadFile(path); err == nil && len(existing) > 0 {
+		seq = existing[len(existing)-1].Seq
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	return &FileJournal{file: f, seq: seq}, nil
+}
+
+func (j *FileJournal) Append(kind Kind, entryType string, playerID string, payload any) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.seq++
+	entry, err := newEntry(j.seq, kind, entryType, playerID, payload)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return nil
+}
+
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+// MemoryJournal はメモリ上に記録するジャーナル。テストやシミュレーションで使う。
+type MemoryJournal struct {
+	entries []Entry
+	mu      sync.Mutex
+}
+
+// NewMemory はメモリ上のジャーナルを生成する
+func NewMemory() *MemoryJournal {
+	return &MemoryJournal{}
+}
+
+func (j *MemoryJournal) Append(kind Kind, entryType string, playerID string, payload any) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, err := newEntry(int64(len(j.entries)+1), kind, entryType, playerID, payload)
+	if err != nil {
+		return err
+	}
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+func (j *MemoryJournal) Close() error { return nil }
+
+// Entries は記録されたエントリのコピーを返す
+func (j *MemoryJournal) Entries() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]Entry(nil), j.entries...)
+}
+
+// ReadFile はジャーナルファイルを読み込み、記録順のエントリを返す
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid journal entry at line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
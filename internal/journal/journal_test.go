@@ -0,0 +1,49 @@
+package journal
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileJournal_AppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.jsonl")
+
+	j, err := OpenFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, j.Append(KindCommand, "ROLL_DICE", "player1", nil))
+	assert.NoError(t, j.Append(KindEvent, "DICE_RESULT", "player1", map[string]any{"diceResult": 4}))
+	assert.NoError(t, j.Close())
+
+	// 再度開いた場合も追記される
+	j, err = OpenFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, j.Append(KindCommand, "SUBMIT_QUIZ", "player1", map[string]any{"selection": 1}))
+	assert.NoError(t, j.Close())
+
+	entries, err := ReadFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, KindCommand, entries[0].Kind)
+	assert.Equal(t, "ROLL_DICE", entries[0].Type)
+	assert.Empty(t, entries[0].Payload)
+
+	var payload map[string]int
+	assert.NoError(t, json.Unmarshal(entries[1].Payload, &payload))
+	assert.Equal(t, 4, payload["diceResult"])
+	assert.Equal(t, "SUBMIT_QUIZ", entries[2].Type)
+	assert.Equal(t, int64(3), entries[2].Seq)
+}
+
+func TestMemoryJournal_Entries(t *testing.T) {
+	j := NewMemory()
+	assert.NoError(t, j.Append(KindCommand, "PLAYER_JOINED", "player1", nil))
+	assert.NoError(t, j.Append(KindEvent, "PLAYER_MOVED", "", map[string]any{"newPosition": 2}))
+
+	entries := j.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, int64(1), entries[0].Seq)
+	assert.Equal(t, int64(2), entries[1].Seq)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin は環境変数 ADMIN_UIDS (カンマ区切り) に含まれるユーザーだけを通す。
+// AuthTokenの後に使う。ADMIN_UIDSが空の場合は誰も通さない。
+func RequireAdmin() gin.HandlerFunc {
+	admins := make(map[string]bool)
+	for _, uid := range strings.Split(os.Getenv("ADMIN_UIDS"), ",") {
+		if uid = strings.TrimSpace(uid); uid != "" {
+			admins[uid] = true
+		}
+	}
+	return func(c *gin.Context) {
+		if !admins[c.GetString("firebase_uid")] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "管理者のみ実行できます"})
+			return
+		}
+		c.Next()
+	}
+}
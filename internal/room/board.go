@@ -0,0 +1,144 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/game"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// ReloadResult は盤面の読み直しの結果
+type ReloadResult struct {
+	Version   int              `json:"version,omitempty"` // 盤面を保存した版の番号(保存先がない場合は省略)
+	TileCount int              `json:"tileCount"`
+	QuizCount int              `json:"quizCount"`
+	Rooms     int              `json:"rooms"`
+	Warnings  []sugoroku.Issue `json:"warnings"`
+}
+
+// SetBoardFiles は盤面を読み直すときに使うファイルを設定する
+func (r *Registry) SetBoardFiles(tilesPath, quizPath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tilesPath = tilesPath
+	r.quizPath = quizPath
+}
+
+// ReloadBoard は盤面とクイズのファイルを読み直して検証し、問題がなければすべての部屋に反映する。
+// 盤面の保存先が設定されている場合は、読み直した盤面を新しい版として保存して使う版にしてから反映する。
+// 検証でエラーが見つかった場合は*sugoroku.ValidationErrorを返す。検証やいずれかの部屋の準備に失敗した場合は、
+// 版の保存も、クイズと盤面の差し替えも行わない。
+func (r *Registry) ReloadBoard(author string) (*ReloadResult, error) {
+	r.mu.RLock()
+	tilesPath, quizPath, boards := r.tilesPath, r.quizPath, r.boards
+	r.mu.RUnlock()
+
+	board, issues, err := sugoroku.LoadBoard(tilesPath, quizPath)
+	if err != nil {
+		return nil, err
+	}
+	// すべての部屋で反映できることを確かめてから、保存と差し替えを行う
+	rooms := r.List()
+	plans := make([]*game.ReloadPlan, len(rooms))
+	for i, room := range rooms {
+		plan, err := room.Manager.PrepareReload(board)
+		if err != nil {
+			return nil, fmt.Errorf("room %s: %w", room.ID, err)
+		}
+		plans[i] = plan
+	}
+	if boards != nil {
+		v, _, err := boards.Import(author, "reload from "+tilesPath, board.Tiles, board.Quizzes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save reloaded board: %w", err)
+		}
+		if err := boards.SetActive(v.Number); err != nil {
+			return nil, fmt.Errorf("failed to activate reloaded board: %w", err)
+		}
+		board.Version = v.Number
+	}
+
+	sugoroku.SetQuizzes(board.Quizzes)
+	// 準備の後に閉じられた部屋だけは反映できないが、閉じた部屋はもう使われない
+	var errs []error
+	for i, plan := range plans {
+		if err := plan.Commit(); err != nil {
+			errs = append(errs, fmt.Errorf("room %s: %w", rooms[i].ID, err))
+		}
+	}
+
+	result := &ReloadResult{
+		Version:   board.Version,
+		TileCount: len(board.Tiles),
+		QuizCount: len(board.Quizzes),
+		Rooms:     len(rooms),
+		Warnings:  issues,
+	}
+	if result.Warnings == nil {
+		result.Warnings = []sugoroku.Issue{}
+	}
+	log.WithFields(log.Fields{
+		"version":  result.Version,
+		"tiles":    result.TileCount,
+		"quizzes":  result.QuizCount,
+		"rooms":    result.Rooms,
+		"warnings": len(issues),
+	}).Info("Board reloaded for all rooms")
+	return result, errors.Join(errs...)
+}
+
+// WatchBoard は一定間隔で盤面とクイズのファイルの更新日時を調べ、変わっていれば読み直す。返り値の関数で停止する。
+// 読み直しに失敗した場合は以前の盤面のまま続け、ファイルが再び更新されたときにもう一度読み直す。
+func (r *Registry) WatchBoard(interval time.Duration) (stop func()) {
+	r.mu.RLock()
+	paths := []string{r.tilesPath, r.quizPath}
+	r.mu.RUnlock()
+
+	last := modTimes(paths)
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				current := modTimes(paths)
+				if equalTimes(last, current) {
+					continue
+				}
+				last = current
+				if _, err := r.ReloadBoard(""); err != nil {
+					log.WithError(err).Error("failed to reload board")
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// modTimes はファイルの更新日時を返す。読めないファイルはゼロ値にする。
+func modTimes(paths []string) []time.Time {
+	times := make([]time.Time, len(paths))
+	for i, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			times[i] = info.ModTime()
+		}
+	}
+	return times
+}
+
+func equalTimes(a, b []time.Time) bool {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
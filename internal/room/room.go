@@ -0,0 +1,347 @@
+package room
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/boardstore"
+	"github.com/shii-park/Metasugo-Backend/internal/game"
+	"github.com/shii-park/Metasugo-Backend/internal/hub"
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// DefaultRoomID は部屋IDを指定せずに接続した場合に使われる部屋のID
+const DefaultRoomID = "default"
+
+var (
+	ErrRoomNotFound    = errors.New("room not found")
+	ErrRoomExists      = errors.New("room already exists")
+	ErrInvalidDice     = errors.New("invalid dice config")
+	ErrInvalidTimeouts = errors.New("invalid decision timeouts")
+	ErrInvalidID       = errors.New("invalid room id")
+)
+
+// 部屋IDはファイル名にも使うため、英数字・ハイフン・アンダースコアに限る
+var validRoomID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Room は1つのすごろく盤面と、それに参加しているクライアントの集合
+type Room struct {
+	ID        string
+	Game      *sugoroku.Game
+	Hub       *hub.Hub
+	Manager   *game.GameManager
+	Journal   journal.Journal // 記録先(ジャーナルを保存しない場合はnil)
+	Options   Options
+	CreatedAt time.Time
+}
+
+// Options は部屋を作成する際の設定
+type Options struct {
+	TurnBased bool                `json:"turnBased"` // 手番制で進行するかどうか
+	Seed      int64               `json:"seed"`      // 乱数のシード(0の場合はランダム)
+	Dice      sugoroku.DiceConfig `json:"dice"`      // サイコロの種類
+	Timeouts  TimeoutOptions      `json:"timeouts"`  // 入力要求の回答期限
+}
+
+// TimeoutOptions は入力要求の種類(branch, quizなど)ごとの回答期限(秒)。指定がないか0の場合は期限なし。
+type TimeoutOptions map[string]int
+
+// AllTimeouts は登録されたすべての入力要求に同じ回答期限を設定したTimeoutOptionsを返す
+func AllTimeouts(seconds int) TimeoutOptions {
+	t := make(TimeoutOptions)
+	for _, kind := range game.PendingKinds() {
+		t[string(kind)] = seconds
+	}
+	return t
+}
+
+// decisionTimeouts は秒単位の回答期限をゲームの回答期限に変換する。登録されていない種類や負の値はエラーにする。
+func (t TimeoutOptions) decisionTimeouts() (game.DecisionTimeouts, error) {
+	timeouts := make(game.DecisionTimeouts, len(t))
+	for kind, seconds := range t {
+		if !game.IsPendingKind(game.PendingKind(kind)) {
+			return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidTimeouts, kind)
+		}
+		if seconds < 0 {
+			return nil, fmt.Errorf("%w: %s must not be negative", ErrInvalidTimeouts, kind)
+		}
+		timeouts[game.PendingKind(kind)] = time.Duration(seconds) * time.Second
+	}
+	return timeouts, nil
+}
+
+// RoomSummary は部屋一覧APIで返す部屋の概要
+type RoomSummary struct {
+	ID          string    `json:"id"`
+	PlayerCount int       `json:"playerCount"`
+	TurnBased   bool      `json:"turnBased"`
+	CurrentTurn string    `json:"currentTurn,omitempty"`
+	Seed        int64     `json:"seed"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Summary は部屋の概要を返す
+func (r *Room) Summary() RoomSummary {
+	return RoomSummary{
+		ID:          r.ID,
+		PlayerCount: len(r.Game.GetAllPlayers()),
+		TurnBased:   r.Manager.IsTurnBased(),
+		CurrentTurn: r.Manager.CurrentTurn(),
+		Seed:        r.Game.Seed(),
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// Registry は稼働中の部屋を管理する
+type Registry struct {
+	rooms       map[string]*Room
+	newGame     func() *sugoroku.Game
+	journalDir  string                    // 空でなければ部屋ごとのジャーナルをこのディレクトリに保存する
+	snapshotDir string                    // 空でなければ部屋ごとのスナップショットをこのディレクトリに保存する
+	tilesPath   string                    // 盤面を読み直すときの盤面のファイル
+	quizPath    string                    // 盤面を読み直すときのクイズのファイル
+	boards      *boardstore.Store         // 設定されていれば、部屋の盤面をこの保存先の版から作る
+	timeouts    map[string]TimeoutOptions // 起動時の設定で決まる部屋の回答期限。復元するときもスナップショットの値より優先する
+
+	mu sync.RWMutex
+}
+
+// NewRegistry は部屋のレジストリを生成する。
+// newGame は部屋を作るたびに呼ばれ、その部屋専用のGameを返す。
+func NewRegistry(newGame func() *sugoroku.Game) *Registry {
+	return &Registry{
+		rooms:     make(map[string]*Room),
+		newGame:   newGame,
+		tilesPath: sugoroku.TilesJSONPath,
+		quizPath:  sugoroku.QuizJSONPath,
+	}
+}
+
+// SetJournalDir は部屋ごとのジャーナルの保存先ディレクトリを設定する。
+// 以降に作成される部屋から有効になる。
+func (r *Registry) SetJournalDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create journal dir: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.journalDir = dir
+	return nil
+}
+
+// SetBoardStore は盤面の版の保存先を設定する。
+// 以降に作成される部屋は使う版の盤面で始まり、スナップショットから復元する部屋は保存したときの版の盤面に戻る。
+func (r *Registry) SetBoardStore(boards *boardstore.Store) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.boards = boards
+}
+
+// SetRoomTimeouts は起動時の設定で決まる部屋の回答期限を設定する。
+// スナップショットからその部屋を復元するときは、保存されていた回答期限ではなくこの値を使う。
+func (r *Registry) SetRoomTimeouts(id string, t TimeoutOptions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timeouts == nil {
+		r.timeouts = make(map[string]TimeoutOptions)
+	}
+	r.timeouts[id] = t
+}
+
+// Create は新しい部屋を作成する。idが空の場合はランダムなIDを割り当てる。
+func (r *Registry) Create(id string, opts Options) (*Room, error) {
+	return r.create(id, opts, 0)
+}
+
+// create は部屋を作成する。boardVersionが0の場合は使う版の盤面を使う。
+func (r *Registry) create(id string, opts Options, boardVersion int) (*Room, error) {
+	if id == "" {
+		generated, err := generateRoomID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate room id: %w", err)
+		}
+		id = generated
+	}
+	if !validRoomID.MatchString(id) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidID, id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rooms[id]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrRoomExists, id)
+	}
+
+	g, err := r.newRoomGame(boardVersion)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Seed != 0 {
+		g.SetSeed(opts.Seed)
+	}
+	if err := g.ConfigureDice(opts.Dice); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDice, err)
+	}
+	timeouts, err := opts.Timeouts.decisionTimeouts()
+	if err != nil {
+		return nil, err
+	}
+
+	h := hub.NewHub()
+	go h.Run()
+
+	gm := game.NewGameManager(g, h)
+	gm.SetTurnBased(opts.TurnBased)
+	gm.SetDecisionTimeouts(timeouts)
+
+	createdAt := time.Now()
+	var j journal.Journal
+	if r.journalDir != "" {
+		name := fmt.Sprintf("%s-%s.jsonl", id, createdAt.Format("20060102-150405"))
+		fj, err := journal.OpenFile(filepath.Join(r.journalDir, name))
+		if err != nil {
+			h.Stop()
+			return nil, err
+		}
+		j = fj
+		gm.SetJournal(j)
+	}
+
+	room := &Room{
+		ID:        id,
+		Game:      g,
+		Hub:       h,
+		Manager:   gm,
+		Journal:   j,
+		Options:   opts,
+		CreatedAt: createdAt,
+	}
+	r.rooms[id] = room
+
+	log.WithFields(log.Fields{"roomID": id, "turnBased": opts.TurnBased}).Info("Room created")
+	return room, nil
+}
+
+// newRoomGame は部屋のGameを生成する。盤面の保存先が設定されていれば、指定した版(0の場合は使う版)の盤面を使う。
+func (r *Registry) newRoomGame(boardVersion int) (*sugoroku.Game, error) {
+	if r.boards == nil {
+		return r.newGame(), nil
+	}
+	v := r.boards.Active()
+	if boardVersion != 0 {
+		var err error
+		if v, err = r.boards.Get(boardVersion); err != nil {
+			return nil, err
+		}
+	}
+	g, err := sugoroku.NewGameFromTiles(v.Tiles, time.Now().UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build board version %d: %w", v.Number, err)
+	}
+	g.SetBoardVersion(v.Number)
+	return g, nil
+}
+
+// Get は指定したIDの部屋を返す
+func (r *Registry) Get(id string) (*Room, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRoomNotFound, id)
+	}
+	return room, nil
+}
+
+// List は稼働中の部屋を作成順に返す
+func (r *Registry) List() []*Room {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rooms := make([]*Room, 0, len(r.rooms))
+	for _, room := range r.rooms {
+		rooms = append(rooms, room)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].CreatedAt.Before(rooms[j].CreatedAt)
+	})
+	return rooms
+}
+
+// Close は部屋を閉じ、参加中のクライアントへ通知してから接続を切断する
+func (r *Registry) Close(id string) error {
+	r.mu.Lock()
+	room, ok := r.rooms[id]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrRoomNotFound, id)
+	}
+	delete(r.rooms, id)
+	snapshotDir := r.snapshotDir
+	r.mu.Unlock()
+
+	room.Hub.Broadcast(map[string]any{
+		"type": "ROOM_CLOSED",
+		"payload": map[string]any{
+			"roomID": id,
+		},
+	})
+	room.shutdown()
+	// 閉じた部屋は再起動後に復元しない
+	if snapshotDir != "" {
+		if err := os.Remove(snapshotPath(snapshotDir, id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.WithError(err).WithField("roomID", id).Error("failed to remove snapshot")
+		}
+	}
+
+	log.WithField("roomID", id).Info("Room closed")
+	return nil
+}
+
+// Shutdown はサーバーの終了時に呼ぶ。すべての部屋のゲームを止めてから状態を保存し、部屋を閉じる。
+// Closeと違ってスナップショットは残すので、次に起動したときに続きから再開できる。
+func (r *Registry) Shutdown() error {
+	rooms := r.List()
+	// 保存した後に状態が変わらないように、先にゲームを止める
+	for _, room := range rooms {
+		room.Manager.Stop()
+	}
+	err := r.SaveSnapshots()
+	for _, room := range rooms {
+		room.shutdown()
+	}
+	return err
+}
+
+// shutdown は部屋の接続を切断し、ゲームとジャーナルを止める
+func (room *Room) shutdown() {
+	// 回答期限のタイマーや処理中のコマンドが、閉じた接続やジャーナルに書き込まないように、先にゲームを止める
+	room.Manager.Stop()
+	room.Hub.Stop()
+	if room.Journal != nil {
+		if err := room.Journal.Close(); err != nil {
+			log.WithError(err).WithField("roomID", room.ID).Error("failed to close journal")
+		}
+	}
+}
+
+func generateRoomID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
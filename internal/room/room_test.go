@@ -0,0 +1,414 @@
+package room
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shii-park/Metasugo-Backend/internal/boardstore"
+	"github.com/shii-park/Metasugo-Backend/internal/game"
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRegistry() *Registry {
+	return NewRegistry(func() *sugoroku.Game {
+		return sugoroku.NewGameWithTilesForTest("../../test/test_tiles.json")
+	})
+}
+
+func TestRegistry_CreateListClose(t *testing.T) {
+	r := newTestRegistry()
+
+	room1, err := r.Create("booth-a", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "booth-a", room1.ID)
+
+	// IDを省略すると自動で採番される
+	room2, err := r.Create("", Options{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, room2.ID)
+
+	// 同じIDの部屋は作成できない
+	_, err = r.Create("booth-a", Options{})
+	assert.ErrorIs(t, err, ErrRoomExists)
+
+	rooms := r.List()
+	assert.Len(t, rooms, 2)
+	assert.Equal(t, "booth-a", rooms[0].ID)
+
+	// 部屋ごとに独立したGameを持つ
+	assert.NotSame(t, room1.Game, room2.Game)
+
+	assert.NoError(t, r.Close("booth-a"))
+	_, err = r.Get("booth-a")
+	assert.ErrorIs(t, err, ErrRoomNotFound)
+	assert.ErrorIs(t, r.Close("booth-a"), ErrRoomNotFound)
+}
+
+func TestRegistry_BroadcastStaysInRoom(t *testing.T) {
+	r := newTestRegistry()
+	roomA, _ := r.Create("a", Options{})
+	roomB, _ := r.Create("b", Options{})
+
+	clientA := roomA.Hub.NewClient(nil, "player1")
+	roomA.Hub.Register(clientA)
+	assert.NoError(t, roomA.Manager.RegisterPlayerClient("player1", clientA))
+
+	clientB := roomB.Hub.NewClient(nil, "player2")
+	roomB.Hub.Register(clientB)
+	assert.NoError(t, roomB.Manager.RegisterPlayerClient("player2", clientB))
+	time.Sleep(10 * time.Millisecond)
+
+	// 部屋Aでの移動は部屋Aのクライアントにのみ届く
+	assert.NoError(t, roomA.Manager.MoveByDiceRoll("player1", 1))
+
+	select {
+	case msg := <-clientA.Send:
+		var event map[string]any
+		assert.NoError(t, json.Unmarshal(msg, &event))
+		assert.Equal(t, "PLAYER_MOVED", event["type"])
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timed out waiting for PLAYER_MOVED in room a")
+	}
+
+	select {
+	case msg := <-clientB.Send:
+		t.Fatalf("client in room b should not receive events from room a: %s", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRegistry_CloseNotifiesClients(t *testing.T) {
+	r := newTestRegistry()
+	roomA, _ := r.Create("a", Options{})
+
+	client := roomA.Hub.NewClient(nil, "player1")
+	roomA.Hub.Register(client)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.NoError(t, r.Close("a"))
+
+	msg, ok := <-client.Send
+	assert.True(t, ok)
+	var event map[string]any
+	assert.NoError(t, json.Unmarshal(msg, &event))
+	assert.Equal(t, "ROOM_CLOSED", event["type"])
+
+	// 通知後は接続が閉じられる
+	_, ok = <-client.Send
+	assert.False(t, ok)
+}
+
+func TestRegistry_CloseStopsDeadlines(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestRegistry()
+	assert.NoError(t, r.SetJournalDir(dir))
+	roomA, err := r.Create("a", Options{Timeouts: AllTimeouts(1)})
+	assert.NoError(t, err)
+	client := roomA.Hub.NewClient(nil, "player1")
+	roomA.Hub.Register(client)
+	assert.NoError(t, roomA.Manager.RegisterPlayerClient("player1", client))
+	time.Sleep(10 * time.Millisecond)
+	// マス4(分岐)で止まり、回答待ちになる
+	roomA.Game.SetDice(sugoroku.NewFixedDice(3))
+	assert.NoError(t, roomA.Manager.HandleMove("player1"))
+
+	assert.NoError(t, r.Close("a"))
+	paths, err := filepath.Glob(filepath.Join(dir, "a-*.jsonl"))
+	assert.NoError(t, err)
+	assert.Len(t, paths, 1)
+	before, err := journal.ReadFile(paths[0])
+	assert.NoError(t, err)
+
+	// 回答期限が過ぎても、閉じた部屋では既定の回答を適用しない
+	time.Sleep(1200 * time.Millisecond)
+	after, err := journal.ReadFile(paths[0])
+	assert.NoError(t, err)
+	assert.Equal(t, len(before), len(after))
+	player, err := roomA.Game.GetPlayer("player1")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, player.Position.Id)
+	assert.ErrorIs(t, roomA.Manager.HandleMove("player1"), game.ErrRoomClosed)
+}
+
+func TestRegistry_SnapshotSaveRestore(t *testing.T) {
+	dir := t.TempDir()
+
+	r := newTestRegistry()
+	assert.NoError(t, r.SetSnapshotDir(dir))
+	roomA, _ := r.Create("a", Options{TurnBased: true, Seed: 3})
+	assert.NoError(t, roomA.Manager.RegisterPlayerClient("player1", nil))
+	player, _ := roomA.Game.GetPlayer("player1")
+	assert.NoError(t, player.Profit(500))
+	assert.NoError(t, r.SaveSnapshots())
+
+	// 再起動を想定して、新しいレジストリに復元する
+	restarted := newTestRegistry()
+	assert.NoError(t, restarted.SetSnapshotDir(dir))
+	n, err := restarted.RestoreSnapshots()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	restoredRoom, err := restarted.Get("a")
+	assert.NoError(t, err)
+	assert.True(t, restoredRoom.Manager.IsTurnBased())
+	restoredPlayer, err := restoredRoom.Game.GetPlayer("player1")
+	assert.NoError(t, err)
+	assert.Equal(t, player.Money, restoredPlayer.Money)
+
+	// 閉じた部屋は復元されない
+	assert.NoError(t, restarted.Close("a"))
+	again := newTestRegistry()
+	assert.NoError(t, again.SetSnapshotDir(dir))
+	n, err = again.RestoreSnapshots()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestRegistry_ShutdownKeepsSnapshots(t *testing.T) {
+	dir := t.TempDir()
+
+	r := newTestRegistry()
+	assert.NoError(t, r.SetSnapshotDir(dir))
+	roomA, _ := r.Create("a", Options{Timeouts: AllTimeouts(60)})
+	roomB, _ := r.Create("b", Options{Timeouts: AllTimeouts(30)})
+	assert.NoError(t, roomA.Manager.RegisterPlayerClient("player1", nil))
+	assert.NoError(t, r.Shutdown())
+
+	// 保存した後はゲームが止まっているので、状態が変わらない
+	assert.ErrorIs(t, roomA.Manager.HandleMove("player1"), game.ErrRoomClosed)
+	assert.FileExists(t, filepath.Join(dir, "a.json"))
+	assert.FileExists(t, filepath.Join(dir, "b.json"))
+
+	// 起動時の設定で回答期限を決めた部屋は、保存されていた回答期限ではなく今の設定で復元する
+	restarted := newTestRegistry()
+	assert.NoError(t, restarted.SetSnapshotDir(dir))
+	restarted.SetRoomTimeouts("a", AllTimeouts(5))
+	n, err := restarted.RestoreSnapshots()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	restoredA, err := restarted.Get("a")
+	assert.NoError(t, err)
+	assert.Equal(t, AllTimeouts(5), restoredA.Options.Timeouts)
+	restoredB, err := restarted.Get("b")
+	assert.NoError(t, err)
+	assert.Equal(t, roomB.Options.Timeouts, restoredB.Options.Timeouts)
+}
+
+func TestRegistry_SnapshotRestoresBoardVersion(t *testing.T) {
+	tiles, err := sugoroku.LoadTilesJSON("../../test/test_tiles.json")
+	assert.NoError(t, err)
+	boards, err := boardstore.Open(t.TempDir(), tiles)
+	assert.NoError(t, err)
+	dir := t.TempDir()
+
+	r := newTestRegistry()
+	r.SetBoardStore(boards)
+	assert.NoError(t, r.SetSnapshotDir(dir))
+	roomA, err := r.Create("a", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, roomA.Game.BoardVersion())
+	assert.NoError(t, roomA.Manager.RegisterPlayerClient("player1", nil))
+	player, _ := roomA.Game.GetPlayer("player1")
+	player.Move(1) // マス2
+	assert.NoError(t, r.SaveSnapshots())
+
+	// マス2を消した版を使う版にする
+	v, _, err := boards.Edit("admin", "", func(tiles []sugoroku.TileJSON) ([]sugoroku.TileJSON, error) {
+		tiles, err := boardstore.DeleteTile(tiles, 2)
+		if err != nil {
+			return nil, err
+		}
+		return boardstore.ReplaceTile(tiles, 1, sugoroku.TileJSON{Kind: "normal", NextIDs: []int{3}})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, boards.SetActive(v.Number))
+
+	// 再起動後も、保存したときの版の盤面で続きから遊べる
+	restarted := newTestRegistry()
+	restarted.SetBoardStore(boards)
+	assert.NoError(t, restarted.SetSnapshotDir(dir))
+	n, err := restarted.RestoreSnapshots()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	restoredRoom, err := restarted.Get("a")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, restoredRoom.Game.BoardVersion())
+	restoredPlayer, err := restoredRoom.Game.GetPlayer("player1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restoredPlayer.Position.Id)
+
+	// 新しい部屋は使う版の盤面で始まる
+	roomB, err := restarted.Create("b", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, v.Number, roomB.Game.BoardVersion())
+}
+
+func TestRegistry_RestoreSkipsBrokenRoom(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestRegistry()
+	assert.NoError(t, r.SetSnapshotDir(dir))
+	roomA, _ := r.Create("a", Options{})
+	assert.NoError(t, roomA.Manager.RegisterPlayerClient("player1", nil))
+	player, _ := roomA.Game.GetPlayer("player1")
+	player.Move(1) // マス2
+	roomB, _ := r.Create("b", Options{})
+	assert.NoError(t, roomB.Manager.RegisterPlayerClient("player2", nil))
+	assert.NoError(t, r.SaveSnapshots())
+
+	// マス2がない盤面では部屋aを復元できないが、部屋bの復元と起動は続ける
+	restarted := NewRegistry(func() *sugoroku.Game {
+		g, err := sugoroku.NewGameFromTiles([]sugoroku.TileJSON{{ID: 1, Kind: "goal", Effect: json.RawMessage(`{"type": "goal"}`)}}, 1)
+		assert.NoError(t, err)
+		return g
+	})
+	assert.NoError(t, restarted.SetSnapshotDir(dir))
+	n, err := restarted.RestoreSnapshots()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = restarted.Get("a")
+	assert.ErrorIs(t, err, ErrRoomNotFound)
+	_, err = restarted.Get("b")
+	assert.NoError(t, err)
+	// 復元できなかった部屋のスナップショットは残す
+	assert.FileExists(t, filepath.Join(dir, "a.json"))
+}
+
+func TestRegistry_InvalidRoomID(t *testing.T) {
+	r := newTestRegistry()
+	_, err := r.Create("../etc", Options{})
+	assert.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestRegistry_Timeouts(t *testing.T) {
+	r := newTestRegistry()
+
+	// 登録された入力要求の種類ごとに回答期限を指定できる
+	assert.Equal(t, TimeoutOptions{"branch": 30, "choice": 30, "gamble": 30, "quiz": 30}, AllTimeouts(30))
+	_, err := r.Create("a", Options{Timeouts: TimeoutOptions{"quiz": 60}})
+	assert.NoError(t, err)
+
+	_, err = r.Create("b", Options{Timeouts: TimeoutOptions{"unknown": 30}})
+	assert.ErrorIs(t, err, ErrInvalidTimeouts)
+	_, err = r.Create("c", Options{Timeouts: TimeoutOptions{"quiz": -1}})
+	assert.ErrorIs(t, err, ErrInvalidTimeouts)
+}
+
+func TestRegistry_ReloadBoard(t *testing.T) {
+	r := newTestRegistry()
+	roomA, _ := r.Create("a", Options{})
+	roomB, _ := r.Create("b", Options{})
+
+	dir := t.TempDir()
+	tilesPath := filepath.Join(dir, "tiles.json")
+	r.SetBoardFiles(tilesPath, "../../test/test_quizzes.json")
+
+	// 検証でエラーになる盤面は反映しない
+	assert.NoError(t, os.WriteFile(tilesPath, []byte(`[{"id": 1, "kind": "teleport", "effect": {"type": "teleport"}, "prev_ids": [], "next_ids": []}]`), 0o644))
+	_, err := r.ReloadBoard("")
+	var validationErr *sugoroku.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Len(t, roomA.Game.Tiles(), 6)
+
+	// 正しい盤面はすべての部屋に反映する
+	assert.NoError(t, os.WriteFile(tilesPath, []byte(`[
+		{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [1], "next_ids": []}
+	]`), 0o644))
+	result, err := r.ReloadBoard("")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TileCount)
+	assert.Equal(t, 2, result.Rooms)
+	assert.Len(t, roomA.Game.Tiles(), 2)
+	assert.Len(t, roomB.Game.Tiles(), 2)
+	// 部屋ごとに別のタイルを使う
+	assert.NotSame(t, roomA.Game.Tiles()[0], roomB.Game.Tiles()[0])
+}
+
+func TestRegistry_ReloadBoardSavesVersion(t *testing.T) {
+	tiles, err := sugoroku.LoadTilesJSON("../../test/test_tiles.json")
+	assert.NoError(t, err)
+	boards, err := boardstore.Open(t.TempDir(), tiles)
+	assert.NoError(t, err)
+	r := newTestRegistry()
+	r.SetBoardStore(boards)
+	roomA, err := r.Create("a", Options{})
+	assert.NoError(t, err)
+
+	tilesPath := filepath.Join(t.TempDir(), "tiles.json")
+	r.SetBoardFiles(tilesPath, "../../test/test_quizzes.json")
+
+	// 検証でエラーになる盤面は版として保存しない
+	assert.NoError(t, os.WriteFile(tilesPath, []byte(`[{"id": 1, "kind": "teleport", "effect": {"type": "teleport"}, "prev_ids": [], "next_ids": []}]`), 0o644))
+	_, err = r.ReloadBoard("admin")
+	assert.Error(t, err)
+	assert.Equal(t, 1, boards.Latest().Number)
+
+	// 読み直した盤面は新しい版として保存し、使う版にしてから部屋に反映する
+	assert.NoError(t, os.WriteFile(tilesPath, []byte(`[
+		{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [1], "next_ids": []}
+	]`), 0o644))
+	result, err := r.ReloadBoard("admin")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Version)
+	assert.Equal(t, 2, boards.Active().Number)
+	assert.Equal(t, "admin", boards.Latest().Author)
+	assert.Equal(t, 2, roomA.Game.BoardVersion())
+	assert.Len(t, roomA.Game.Tiles(), 2)
+}
+
+func TestRegistry_ReloadBoardIsAtomic(t *testing.T) {
+	tiles, err := sugoroku.LoadTilesJSON("../../test/test_tiles.json")
+	assert.NoError(t, err)
+	boards, err := boardstore.Open(t.TempDir(), tiles)
+	assert.NoError(t, err)
+	r := newTestRegistry()
+	r.SetBoardStore(boards)
+	roomA, err := r.Create("a", Options{})
+	assert.NoError(t, err)
+	roomB, err := r.Create("b", Options{})
+	assert.NoError(t, err)
+
+	original := sugoroku.LoadedQuizzes()
+	t.Cleanup(func() { sugoroku.SetQuizzes(original) })
+	sugoroku.SetQuizzes(nil)
+
+	tilesPath := filepath.Join(t.TempDir(), "tiles.json")
+	r.SetBoardFiles(tilesPath, "../../test/test_quizzes.json")
+	assert.NoError(t, os.WriteFile(tilesPath, []byte(`[
+		{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [1], "next_ids": []}
+	]`), 0o644))
+
+	// 反映できない部屋が1つでもあれば、版の保存もクイズと盤面の差し替えもしない
+	roomB.Manager.Stop()
+	_, err = r.ReloadBoard("admin")
+	assert.ErrorIs(t, err, game.ErrRoomClosed)
+	assert.Equal(t, 1, boards.Latest().Number)
+	assert.Nil(t, sugoroku.LoadedQuizzes())
+	assert.Len(t, roomA.Game.Tiles(), 6)
+}
+
+func TestRegistry_WatchBoard(t *testing.T) {
+	r := newTestRegistry()
+	roomA, _ := r.Create("a", Options{})
+
+	tilesPath := filepath.Join(t.TempDir(), "tiles.json")
+	assert.NoError(t, os.WriteFile(tilesPath, []byte(`[
+		{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [1], "next_ids": []}
+	]`), 0o644))
+	r.SetBoardFiles(tilesPath, "../../test/test_quizzes.json")
+	stop := r.WatchBoard(10 * time.Millisecond)
+	defer stop()
+
+	// 更新日時が変わると読み直す
+	assert.NoError(t, os.Chtimes(tilesPath, time.Now(), time.Now().Add(time.Minute)))
+	assert.Eventually(t, func() bool { return len(roomA.Game.Tiles()) == 2 }, time.Second, 10*time.Millisecond)
+}
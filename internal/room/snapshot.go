@@ -0,0 +1,203 @@
+package room
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/shii-park/Metasugo-Backend/internal/game"
+)
+
+// roomSnapshot は部屋ごとに保存するスナップショットファイルの中身
+type roomSnapshot struct {
+	ID        string        `json:"id"`
+	Options   Options       `json:"options"`
+	CreatedAt time.Time     `json:"createdAt"`
+	SavedAt   time.Time     `json:"savedAt"`
+	State     game.Snapshot `json:"state"`
+}
+
+// SetSnapshotDir はスナップショットの保存先ディレクトリを設定する
+func (r *Registry) SetSnapshotDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot dir: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshotDir = dir
+	return nil
+}
+
+// SaveSnapshots は稼働中のすべての部屋の状態をファイルに保存する
+func (r *Registry) SaveSnapshots() error {
+	r.mu.RLock()
+	dir := r.snapshotDir
+	r.mu.RUnlock()
+	if dir == "" {
+		return nil
+	}
+
+	var errs []error
+	for _, room := range r.List() {
+		if err := room.saveSnapshot(dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RestoreSnapshots は保存先ディレクトリのスナップショットから部屋を復元し、復元した部屋の数を返す。
+// 既に同じIDの部屋がある場合は、その部屋の状態を置き換える。復元できない部屋はログに残して飛ばす。
+func (r *Registry) RestoreSnapshots() (int, error) {
+	r.mu.RLock()
+	dir := r.snapshotDir
+	r.mu.RUnlock()
+	if dir == "" {
+		return 0, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	restored := 0
+	for _, path := range files {
+		snapshot, err := readSnapshot(path)
+		if err != nil {
+			log.WithError(err).WithField("path", path).Error("Skipping unreadable snapshot")
+			continue
+		}
+
+		if err := r.restoreRoom(snapshot); err != nil {
+			// 復元できない部屋があっても、他の部屋の復元とサーバーの起動は続ける。スナップショットは調べられるように残す
+			log.WithError(err).WithField("roomID", snapshot.ID).Error("Skipping room that could not be restored")
+			continue
+		}
+		restored++
+		log.WithFields(log.Fields{
+			"roomID":  snapshot.ID,
+			"players": len(snapshot.State.Game.Players),
+			"savedAt": snapshot.SavedAt,
+		}).Info("Room restored from snapshot")
+	}
+	return restored, nil
+}
+
+// restoreRoom はスナップショットから部屋を1つ復元する。
+// 部屋は保存したときの盤面の版で作り直すので、その後に使う版が変わっていても同じマスから続けられる。
+// SetRoomTimeoutsで回答期限を設定した部屋は、保存されていた回答期限ではなく今の設定を使う。
+func (r *Registry) restoreRoom(snapshot *roomSnapshot) error {
+	opts := snapshot.Options
+	r.mu.RLock()
+	if t, ok := r.timeouts[snapshot.ID]; ok {
+		opts.Timeouts = t
+	}
+	r.mu.RUnlock()
+
+	version := snapshot.State.Game.BoardVersion
+	room, err := r.Get(snapshot.ID)
+	created := false
+	if err != nil {
+		room, err = r.create(snapshot.ID, opts, version)
+		if err != nil {
+			return fmt.Errorf("failed to recreate room: %w", err)
+		}
+		created = true
+	} else if current := room.Game.BoardVersion(); current != version {
+		return fmt.Errorf("room is running board version %d but snapshot was saved on version %d", current, version)
+	} else {
+		// 回答待ちの入力の期限は復元するときに数え直すので、先に回答期限を合わせておく
+		timeouts, err := opts.Timeouts.decisionTimeouts()
+		if err != nil {
+			return err
+		}
+		room.Manager.SetDecisionTimeouts(timeouts)
+	}
+
+	if err := room.Manager.Restore(snapshot.State); err != nil {
+		if created {
+			r.discard(room)
+		}
+		return fmt.Errorf("failed to restore room: %w", err)
+	}
+	room.Options = opts
+	return nil
+}
+
+// discard は復元に失敗した部屋を、スナップショットを消さずに取り除く
+func (r *Registry) discard(room *Room) {
+	r.mu.Lock()
+	delete(r.rooms, room.ID)
+	r.mu.Unlock()
+	room.shutdown()
+}
+
+// StartAutoSnapshot は一定間隔でスナップショットを保存する。返り値の関数で停止する。
+func (r *Registry) StartAutoSnapshot(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.SaveSnapshots(); err != nil {
+					log.WithError(err).Error("failed to save snapshots")
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// saveSnapshot は部屋の状態を一時ファイルに書いてから置き換え、書き込み途中のファイルが残らないようにする
+func (room *Room) saveSnapshot(dir string) error {
+	data, err := json.MarshalIndent(roomSnapshot{
+		ID:        room.ID,
+		Options:   room.Options,
+		CreatedAt: room.CreatedAt,
+		SavedAt:   time.Now(),
+		State:     room.Manager.Snapshot(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot of room %s: %w", room.ID, err)
+	}
+
+	path := snapshotPath(dir, room.ID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot of room %s: %w", room.ID, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace snapshot of room %s: %w", room.ID, err)
+	}
+	return nil
+}
+
+func readSnapshot(path string) (*roomSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot roomSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.ID == "" {
+		snapshot.ID = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	return &snapshot, nil
+}
+
+func snapshotPath(dir, roomID string) string {
+	return filepath.Join(dir, roomID+".json")
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt" // ★ インポート追加
 	"log"
@@ -15,10 +14,6 @@ import (
 )
 
 var (
-	loadOnce  sync.Once
-	loadErr   error
-	tilesData map[string]any
-
 	// --- Firebase 関連の変数を整理 ---
 	firebaseApp *firebase.App
 	appOnce     sync.Once
@@ -126,35 +121,3 @@ func GetAuthClient() (*auth.Client, error) {
 }
 
 // ★★★ ここまで追加 ★★★
-
-func GetTiles() (interface{}, error) {
-	// この関数は変更なし
-	loadOnce.Do(func() {
-		filePath := os.Getenv("TILES_JSON_PATH")
-		if filePath == "" {
-			loadErr = errors.New("TILES_JSON_PATH environment variable not set")
-			return
-		}
-		file, err := os.Open(filePath)
-		if err != nil {
-			loadErr = err
-			return
-		}
-		defer file.Close()
-		var m map[string]any
-
-		decoder := json.NewDecoder(file)
-		if err = decoder.Decode(&m); err != nil {
-			loadErr = err
-			return
-		}
-		tilesData = m
-	})
-	if loadErr != nil {
-		return nil, loadErr
-	}
-	if tilesData == nil {
-		return nil, errors.New("盤面データを読み込めませんでした")
-	}
-	return tilesData, nil
-}
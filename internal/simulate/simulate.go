@@ -0,0 +1,460 @@
+// Package simulate はクライアントなしでゲームを大量に進め、盤面のバランスを集計する
+package simulate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/shii-park/Metasugo-Backend/internal/game"
+	"github.com/shii-park/Metasugo-Backend/internal/journal"
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+)
+
+// 分岐の選び方
+const (
+	BranchFirst  = "first"
+	BranchLast   = "last"
+	BranchRandom = "random"
+)
+
+// GambleRandom はhigh_lowのギャンブルでHighとLowをランダムに選ぶ選び方。
+// 常に同じ方に賭ける場合はsugoroku.GambleHighかsugoroku.GambleLowを指定する
+const GambleRandom = "random"
+
+// Strategy はプレイヤーが入力を求められたときの選び方
+type Strategy struct {
+	Branch         string  // 分岐で選ぶ行き先と、選択マスで選ぶ選択肢
+	QuizAccuracy   float64 // クイズに正解する確率(0〜1)
+	GambleChoice   string  // ギャンブルで賭ける方
+	GambleBetRatio float64 // ギャンブルで所持金のうち賭ける割合(0〜1)
+}
+
+// DefaultStrategy はランダムに分岐を選び、クイズに半分正解し、所持金の1割を賭ける
+var DefaultStrategy = Strategy{
+	Branch:         BranchRandom,
+	QuizAccuracy:   0.5,
+	GambleChoice:   GambleRandom,
+	GambleBetRatio: 0.1,
+}
+
+// Config はシミュレーションの設定
+type Config struct {
+	NewGame  func() *sugoroku.Game // 1ゲームごとに呼ばれ、新しい盤面を返す
+	Games    int                   // 実行するゲームの数
+	Players  int                   // 1ゲームあたりのプレイヤー数
+	Seed     int64                 // 乱数のシード。ゲームごとにSeed+iを使う
+	MaxTurns int                   // ゴールしないプレイヤーを打ち切る手番数
+	Dice     sugoroku.DiceConfig
+	Strategy Strategy
+}
+
+// Stats は数値の分布の要約
+type Stats struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"`
+	Min    float64 `json:"min"`
+	P10    float64 `json:"p10"`
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"`
+	Max    float64 `json:"max"`
+}
+
+// TileStat はマスごとの集計
+type TileStat struct {
+	TileID       int               `json:"tileID"`
+	Kind         sugoroku.TileKind `json:"kind"`
+	Landings     int               `json:"landings"`
+	LandingRate  float64           `json:"landingRate"`  // 1プレイヤーが1ゲームで止まる平均回数
+	AvgMoney     float64           `json:"avgMoney"`     // 止まったときの所持金の平均変化
+	TotalMoney   int               `json:"totalMoney"`   // 止まったときの所持金の変化の合計
+	MoneyChanges int               `json:"moneyChanges"` // 所持金が変化した回数
+	Errors       int               `json:"errors"`       // 効果の適用に失敗した回数
+}
+
+// Report はシミュレーションの結果
+type Report struct {
+	Games       int        `json:"games"`
+	Players     int        `json:"players"`
+	Finished    int        `json:"finished"`    // ゴールしたプレイヤー数
+	Unfinished  int        `json:"unfinished"`  // MaxTurnsまでにゴールしなかったプレイヤー数
+	FinalMoney  Stats      `json:"finalMoney"`  // ゴール時の所持金
+	TurnsToGoal Stats      `json:"turnsToGoal"` // ゴールまでにサイコロを振った回数
+	Errors      int        `json:"errors"`      // ゲームの進行中に起きたエラーの数
+	Tiles       []TileStat `json:"tiles"`
+}
+
+// Run はシミュレーションを実行する
+func Run(cfg Config) (*Report, error) {
+	if cfg.NewGame == nil {
+		return nil, errors.New("NewGame is required")
+	}
+	if cfg.Games <= 0 || cfg.Players <= 0 {
+		return nil, errors.New("games and players must be positive")
+	}
+	if cfg.MaxTurns <= 0 {
+		cfg.MaxTurns = 200
+	}
+
+	acc := newAccumulator()
+	var kinds map[int]sugoroku.TileKind
+	for i := 0; i < cfg.Games; i++ {
+		g := cfg.NewGame()
+		if kinds == nil {
+			kinds = make(map[int]sugoroku.TileKind)
+			for _, t := range g.Tiles() {
+				kinds[t.Id] = t.Kind()
+			}
+		}
+		if err := playGame(g, cfg, cfg.Seed+int64(i), acc); err != nil {
+			return nil, fmt.Errorf("game %d: %w", i, err)
+		}
+	}
+	return acc.report(cfg, kinds), nil
+}
+
+// runner は1ゲーム分の進行と、ジャーナルからの状態の読み取りを行う
+type runner struct {
+	gm       *game.GameManager
+	mem      *journal.MemoryJournal
+	read     int // 読み終えたジャーナルのエントリ数
+	strategy Strategy
+	rng      *rand.Rand
+	acc      *accumulator
+
+	money    map[string]int
+	position map[string]int
+	turns    map[string]int
+	finished map[string]bool
+	prompt   map[string]journal.Entry // 回答待ちの入力要求
+	actor    string                   // 手番を進めているプレイヤー
+}
+
+func playGame(g *sugoroku.Game, cfg Config, seed int64, acc *accumulator) error {
+	g.SetSeed(seed)
+	if err := g.ConfigureDice(cfg.Dice); err != nil {
+		return err
+	}
+
+	r := &runner{
+		gm:       game.NewHeadlessGameManager(g),
+		mem:      journal.NewMemory(),
+		strategy: cfg.Strategy,
+		rng:      rand.New(rand.NewSource(seed)),
+		acc:      acc,
+		money:    make(map[string]int),
+		position: make(map[string]int),
+		turns:    make(map[string]int),
+		finished: make(map[string]bool),
+		prompt:   make(map[string]journal.Entry),
+	}
+	r.gm.SetJournal(r.mem)
+
+	ids := make([]string, cfg.Players)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("sim-%d", i+1)
+		if err := r.gm.RegisterPlayerClient(ids[i], nil); err != nil {
+			return err
+		}
+		p, err := g.GetPlayer(ids[i])
+		if err != nil {
+			return err
+		}
+		r.money[ids[i]] = p.Money
+		r.position[ids[i]] = p.Position.Id
+	}
+
+	for turn := 0; turn < cfg.MaxTurns; turn++ {
+		active := 0
+		for _, id := range ids {
+			if r.finished[id] {
+				continue
+			}
+			active++
+			r.turns[id]++
+			r.actor = id
+			// 実際のゲームと同じく、エラーになっても記録して続ける
+			if err := r.gm.HandleMove(id); err != nil {
+				r.recordError(id)
+			}
+			r.answerPrompts(id)
+		}
+		if active == 0 {
+			break
+		}
+	}
+
+	for _, id := range ids {
+		if !r.finished[id] {
+			acc.unfinished++
+		}
+	}
+	return nil
+}
+
+// answerPrompts は戦略に従って、入力要求がなくなるまで回答する
+func (r *runner) answerPrompts(playerID string) {
+	for {
+		r.consume()
+		entry, ok := r.prompt[playerID]
+		if !ok {
+			return
+		}
+		delete(r.prompt, playerID)
+
+		var err error
+		switch entry.Type {
+		case "BRANCH_CHOICE_REQUIRED":
+			err = r.answerBranch(playerID, entry)
+		case "QUIZ_REQUIRED":
+			err = r.answerQuiz(playerID, entry)
+		case "GAMBLE_REQUIRED":
+			err = r.answerGamble(playerID, entry)
+		case "EFFECT_CHOICE_REQUIRED":
+			err = r.answerEffectChoice(playerID, entry)
+		}
+		if err != nil {
+			r.recordError(playerID)
+			return
+		}
+	}
+}
+
+// recordError はプレイヤーが今いるマスでエラーが起きたことを記録する
+func (r *runner) recordError(playerID string) {
+	r.consume()
+	r.acc.errors[r.position[playerID]]++
+	r.acc.errorCount++
+}
+
+func (r *runner) answerBranch(playerID string, entry journal.Entry) error {
+	var payload struct {
+		Options []int `json:"options"`
+	}
+	if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+		return err
+	}
+	if len(payload.Options) == 0 {
+		return errors.New("branch has no options")
+	}
+
+	choice := payload.Options[0]
+	switch r.strategy.Branch {
+	case BranchLast:
+		choice = payload.Options[len(payload.Options)-1]
+	case BranchRandom:
+		choice = payload.Options[r.rng.Intn(len(payload.Options))]
+	}
+	return r.gm.HandleBranch(playerID, map[string]any{"selection": float64(choice)})
+}
+
+func (r *runner) answerEffectChoice(playerID string, entry journal.Entry) error {
+	var payload struct {
+		Options []sugoroku.ChoiceOptionInfo `json:"options"`
+	}
+	if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+		return err
+	}
+	if len(payload.Options) == 0 {
+		return errors.New("choice has no options")
+	}
+
+	choice := payload.Options[0].Index
+	switch r.strategy.Branch {
+	case BranchLast:
+		choice = payload.Options[len(payload.Options)-1].Index
+	case BranchRandom:
+		choice = payload.Options[r.rng.Intn(len(payload.Options))].Index
+	}
+	return r.gm.HandleEffectChoice(playerID, map[string]any{"selection": float64(choice)})
+}
+
+func (r *runner) answerQuiz(playerID string, entry journal.Entry) error {
+	var payload struct {
+		QuizData sugoroku.QuizQuestion `json:"quizData"`
+	}
+	if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+		return err
+	}
+	// クライアントには答えが送られないので、読み込み済みのクイズから答えを調べる
+	quiz, ok := sugoroku.FindQuiz(payload.QuizData.ID)
+	if !ok {
+		return fmt.Errorf("quiz %d not found", payload.QuizData.ID)
+	}
+
+	selection := quiz.AnswerIndex
+	if r.rng.Float64() >= r.strategy.QuizAccuracy && len(quiz.Options) > 1 {
+		// 不正解の選択肢から選ぶ
+		selection = (quiz.AnswerIndex + 1 + r.rng.Intn(len(quiz.Options)-1)) % len(quiz.Options)
+	}
+	return r.gm.HandleQuiz(playerID, map[string]any{"selection": float64(selection)})
+}
+
+func (r *runner) answerGamble(playerID string, entry journal.Entry) error {
+	var payload struct {
+		Game    sugoroku.GambleGame `json:"game"`
+		MinBet  int                 `json:"minBet"`
+		MaxBet  int                 `json:"maxBet"`
+		Options []any               `json:"options"`
+	}
+	if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+		return err
+	}
+
+	// 所持金の一定の割合を、マスで決められた範囲に収めて賭ける
+	bet := int(float64(r.money[playerID]) * r.strategy.GambleBetRatio)
+	bet = max(payload.MinBet, min(bet, payload.MaxBet))
+
+	var choice any
+	switch payload.Game {
+	case sugoroku.GambleHighLow:
+		choice = r.strategy.GambleChoice
+		if choice != sugoroku.GambleHigh && choice != sugoroku.GambleLow {
+			choice = sugoroku.GambleHigh
+			if r.rng.Intn(2) == 0 {
+				choice = sugoroku.GambleLow
+			}
+		}
+	case sugoroku.GambleExact:
+		// 予想する目は戦略によらずランダムに選ぶ
+		if len(payload.Options) == 0 {
+			return errors.New("gamble has no options")
+		}
+		choice = payload.Options[r.rng.Intn(len(payload.Options))]
+	}
+	return r.gm.HandleGamble(playerID, map[string]any{
+		"bet":    float64(bet),
+		"choice": choice,
+	})
+}
+
+// consume はまだ読んでいないジャーナルのイベントを読み、状態と集計を更新する
+func (r *runner) consume() {
+	entries := r.mem.Entries()
+	for _, entry := range entries[r.read:] {
+		if entry.Kind != journal.KindEvent {
+			continue
+		}
+		var payload struct {
+			UserID      string `json:"userID"`
+			NewPosition int    `json:"newPosition"`
+			NewMoney    int    `json:"newMoney"`
+			Money       int    `json:"money"`
+		}
+		_ = json.Unmarshal(entry.Payload, &payload)
+
+		switch entry.Type {
+		case "PLAYER_MOVED":
+			r.position[payload.UserID] = payload.NewPosition
+			r.acc.landings[payload.NewPosition]++
+		case "MONEY_CHANGED":
+			// ほかのプレイヤーの効果で変わった所持金は、止まったマスの影響として数えない
+			if payload.UserID == r.actor {
+				tile := r.position[payload.UserID]
+				r.acc.impact[tile] += payload.NewMoney - r.money[payload.UserID]
+				r.acc.moneyChanges[tile]++
+			}
+			r.money[payload.UserID] = payload.NewMoney
+		case "PLAYER_FINISHED":
+			r.finished[payload.UserID] = true
+			r.acc.finalMoney = append(r.acc.finalMoney, float64(payload.Money))
+			r.acc.turns = append(r.acc.turns, float64(r.turns[payload.UserID]))
+		case "BRANCH_CHOICE_REQUIRED", "QUIZ_REQUIRED", "GAMBLE_REQUIRED", "EFFECT_CHOICE_REQUIRED":
+			r.prompt[entry.PlayerID] = entry
+		}
+	}
+	r.read = len(entries)
+}
+
+type accumulator struct {
+	landings     map[int]int
+	impact       map[int]int
+	moneyChanges map[int]int
+	errors       map[int]int
+	errorCount   int
+	finalMoney   []float64
+	turns        []float64
+	unfinished   int
+}
+
+func newAccumulator() *accumulator {
+	return &accumulator{
+		landings:     make(map[int]int),
+		impact:       make(map[int]int),
+		moneyChanges: make(map[int]int),
+		errors:       make(map[int]int),
+	}
+}
+
+func (a *accumulator) report(cfg Config, kinds map[int]sugoroku.TileKind) *Report {
+	rep := &Report{
+		Games:       cfg.Games,
+		Players:     cfg.Players,
+		Finished:    len(a.finalMoney),
+		Unfinished:  a.unfinished,
+		FinalMoney:  summarize(a.finalMoney),
+		TurnsToGoal: summarize(a.turns),
+		Errors:      a.errorCount,
+	}
+
+	playerGames := float64(cfg.Games * cfg.Players)
+	for id, kind := range kinds {
+		stat := TileStat{
+			TileID:       id,
+			Kind:         kind,
+			Landings:     a.landings[id],
+			LandingRate:  float64(a.landings[id]) / playerGames,
+			TotalMoney:   a.impact[id],
+			MoneyChanges: a.moneyChanges[id],
+			Errors:       a.errors[id],
+		}
+		if stat.Landings > 0 {
+			stat.AvgMoney = float64(stat.TotalMoney) / float64(stat.Landings)
+		}
+		rep.Tiles = append(rep.Tiles, stat)
+	}
+	sort.Slice(rep.Tiles, func(i, j int) bool { return rep.Tiles[i].TileID < rep.Tiles[j].TileID })
+	return rep
+}
+
+func summarize(values []float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+	var sq float64
+	for _, v := range sorted {
+		sq += (v - mean) * (v - mean)
+	}
+
+	return Stats{
+		Count:  len(sorted),
+		Mean:   mean,
+		StdDev: math.Sqrt(sq / float64(len(sorted))),
+		Min:    sorted[0],
+		P10:    percentile(sorted, 0.1),
+		P50:    percentile(sorted, 0.5),
+		P90:    percentile(sorted, 0.9),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentile はソート済みの値から最近傍法で百分位数を求める
+func percentile(sorted []float64, p float64) float64 {
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
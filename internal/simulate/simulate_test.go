@@ -0,0 +1,54 @@
+package simulate
+
+import (
+	"testing"
+
+	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGame() *sugoroku.Game {
+	return sugoroku.NewGameWithTilesForTest("../../test/branch_effect_test_tiles.json")
+}
+
+func TestRun_FixedDice(t *testing.T) {
+	report, err := Run(Config{
+		NewGame:  newTestGame,
+		Games:    10,
+		Players:  3,
+		Seed:     1,
+		Dice:     sugoroku.DiceConfig{Fixed: []int{1}},
+		Strategy: DefaultStrategy,
+	})
+	assert.NoError(t, err)
+
+	// 1マスずつ進むので、全員が利益マスを通って2手でゴールする
+	assert.Equal(t, 30, report.Finished)
+	assert.Equal(t, 0, report.Unfinished)
+	assert.Equal(t, 2.0, report.TurnsToGoal.Mean)
+	assert.Equal(t, 0.0, report.FinalMoney.StdDev)
+
+	byID := make(map[int]TileStat)
+	for _, tile := range report.Tiles {
+		byID[tile.TileID] = tile
+	}
+	assert.Equal(t, 30, byID[2].Landings)
+	assert.Equal(t, 1.0, byID[2].LandingRate)
+	assert.Equal(t, 100.0, byID[2].AvgMoney)
+	assert.Equal(t, 0, byID[3].Landings)
+}
+
+func TestRun_SameSeedSameReport(t *testing.T) {
+	cfg := Config{
+		NewGame:  newTestGame,
+		Games:    20,
+		Players:  2,
+		Seed:     42,
+		Strategy: DefaultStrategy,
+	}
+	first, err := Run(cfg)
+	assert.NoError(t, err)
+	second, err := Run(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,169 @@
+package sugoroku
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Board は検証済みの盤面とクイズ
+type Board struct {
+	Version int // 盤面の保存先の版の番号(版として保存していない場合は0)
+	Tiles   []TileJSON
+	Quizzes []Quiz
+}
+
+// LoadBoard は盤面とクイズのファイルを読み込み、組み合わせて検証する。
+// エラーがある場合は*ValidationErrorを返す。警告は返り値のissuesで返す。
+func LoadBoard(tilesPath, quizPath string) (*Board, []Issue, error) {
+	tiles, err := LoadTilesJSON(tilesPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load tiles: %w", err)
+	}
+	quizList, err := LoadQuizzes(quizPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load quizzes: %w", err)
+	}
+
+	issues := ValidateTiles(tiles, quizList)
+	if errs := ErrorIssues(issues); len(errs) > 0 {
+		return nil, issues, &ValidationError{Issues: errs}
+	}
+	return &Board{Tiles: tiles, Quizzes: quizList}, issues, nil
+}
+
+// TileMap は盤面のタイルを生成する。ゲームごとに別のタイルを使うので、呼ぶたびに作り直す。
+func (b *Board) TileMap() (map[int]*Tile, error) {
+	return buildTiles(b.Tiles)
+}
+
+// BoardView はクライアントに返す盤面。マスはID順に並び、すべてのマスに座標(position)が入る。
+// Versionは盤面の保存先の版の番号(保存された版から作っていない場合は0)。
+// Hashは盤面の内容から計算するので、同じ盤面なら部屋やサーバーの再起動をまたいでも同じ値になる。
+type BoardView struct {
+	Version int        `json:"version"`
+	Hash    string     `json:"hash"`
+	Tiles   []TileJSON `json:"tiles"`
+}
+
+// Board はゲームが使っている盤面を返す
+func (g *Game) Board() BoardView {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	view := g.board
+	view.Version = g.boardVersion
+	return view
+}
+
+// SetBoardVersion はゲームの盤面が保存先のどの版から作られたかを記録する
+func (g *Game) SetBoardVersion(number int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.boardVersion = number
+}
+
+// BoardVersion はゲームの盤面の保存先の版の番号を返す。保存された版から作っていない場合は0。
+func (g *Game) BoardVersion() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.boardVersion
+}
+
+func newBoardView(tileMap map[int]*Tile) BoardView {
+	positions := layoutTiles(tileMap)
+	tiles := make([]TileJSON, 0, len(tileMap))
+	for _, t := range tileMap {
+		position := positions[t.Id]
+		tiles = append(tiles, TileJSON{
+			ID:       t.Id,
+			Kind:     t.kind,
+			Detail:   t.detail,
+			Effect:   t.effectJSON,
+			PrevIDs:  tileIDs(t.prevs),
+			NextIDs:  tileIDs(t.nexts),
+			Position: &position,
+		})
+	}
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].ID < tiles[j].ID })
+
+	data, err := json.Marshal(tiles)
+	if err != nil {
+		// 読み込めた盤面は必ずJSONに戻せる
+		panic(fmt.Sprintf("failed to marshal board: %v", err))
+	}
+	sum := sha256.Sum256(data)
+	return BoardView{Hash: hex.EncodeToString(sum[:8]), Tiles: tiles}
+}
+
+func tileIDs(tiles []*Tile) []int {
+	ids := make([]int, 0, len(tiles))
+	for _, t := range tiles {
+		ids = append(ids, t.Id)
+	}
+	return ids
+}
+
+// ReplaceTiles は盤面を差し替え、プレイヤーを同じIDのマスへ移す。
+// 新しい盤面にないマスにいたプレイヤーはスタートに戻し、そのIDを返す。
+func (g *Game) ReplaceTiles(tileMap map[int]*Tile) ([]string, error) {
+	start, ok := tileMap[InitialTileID]
+	if !ok {
+		return nil, errors.New("new board has no start tile")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ids := make([]string, 0, len(g.players))
+	for id := range g.players {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var relocated []string
+	for _, id := range ids {
+		if !g.players[id].remapTiles(tileMap, start) {
+			relocated = append(relocated, id)
+		}
+	}
+	g.tileMap = tileMap
+	g.board = newBoardView(tileMap)
+	return relocated, nil
+}
+
+// remapTiles は現在地と通ってきた道を新しい盤面の同じIDのマスに置き換える。
+// 現在地が新しい盤面にない場合はスタートに戻してfalseを返す。
+func (p *Player) remapTiles(tileMap map[int]*Tile, start *Tile) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tile, ok := tileMap[p.Position.Id]
+	if !ok {
+		p.Position = start
+		p.trail = nil
+		p.held = false
+		p.remaining = 0
+		return false
+	}
+	p.Position = tile
+
+	// 通ってきた道は、なくなったマスより前を捨てる
+	trail := make([]*Tile, 0, len(p.trail))
+	for _, t := range p.trail {
+		next, ok := tileMap[t.Id]
+		if !ok {
+			trail = trail[:0]
+			continue
+		}
+		trail = append(trail, next)
+	}
+	p.trail = trail
+	// 関所ではなくなったマスでは足止めを解く
+	if _, ok := tile.Effect.(RequireEffect); !ok {
+		p.held = false
+	}
+	return true
+}
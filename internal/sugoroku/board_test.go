@@ -0,0 +1,94 @@
+package sugoroku
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const reloadedBoardJSON = `[
+	{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+	{"id": 2, "kind": "profit", "effect": {"type": "profit", "amount": 99}, "prev_ids": [1], "next_ids": [3]},
+	{"id": 3, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+]`
+
+func TestLoadBoard(t *testing.T) {
+	tilesPath := CreateTestFile(t, "board_*.json", reloadedBoardJSON)
+	defer os.Remove(tilesPath)
+
+	board, _, err := LoadBoard(tilesPath, "../../test/test_quizzes.json")
+	assert.NoError(t, err)
+	assert.Len(t, board.Tiles, 3)
+	assert.NotEmpty(t, board.Quizzes)
+
+	brokenPath := CreateTestFile(t, "broken_*.json", `[{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]}]`)
+	defer os.Remove(brokenPath)
+	_, _, err = LoadBoard(brokenPath, "../../test/test_quizzes.json")
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestGame_ReplaceTiles(t *testing.T) {
+	g := NewGameWithTilesForTest("../../test/test_tiles.json")
+	stay, _ := g.AddPlayer("stay")
+	gone, _ := g.AddPlayer("gone")
+	stay.Move(1) // マス2
+	gone.Move(2) // マス3 (クイズ)
+
+	tilesPath := CreateTestFile(t, "board_*.json", `[
+		{"id": 1, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "profit", "effect": {"type": "profit", "amount": 99}, "prev_ids": [1], "next_ids": [4]},
+		{"id": 4, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [2], "next_ids": []}
+	]`)
+	defer os.Remove(tilesPath)
+	board, _, err := LoadBoard(tilesPath, "../../test/test_quizzes.json")
+	assert.NoError(t, err)
+	tileMap, err := board.TileMap()
+	assert.NoError(t, err)
+
+	relocated, err := g.ReplaceTiles(tileMap)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gone"}, relocated)
+
+	// 同じIDの新しいマスへ移り、新しい効果が使われる
+	assert.Same(t, tileMap[2], stay.Position)
+	assert.Equal(t, ProfitEffect{Amount: 99}, stay.Position.Effect)
+	assert.Same(t, tileMap[InitialTileID], gone.Position)
+
+	_, err = g.ReplaceTiles(map[int]*Tile{})
+	assert.Error(t, err)
+}
+
+func TestGame_Board(t *testing.T) {
+	g := NewGameWithTilesForTest("../../test/test_tiles.json")
+	view := g.Board()
+	assert.NotEmpty(t, view.Hash)
+	assert.Equal(t, 0, view.Version)
+	assert.Equal(t, InitialTileID, view.Tiles[0].ID)
+	assert.Len(t, view.Tiles, len(g.Tiles()))
+
+	// 同じ盤面ならハッシュも同じになる
+	assert.Equal(t, view.Hash, NewGameWithTilesForTest("../../test/test_tiles.json").Board().Hash)
+
+	tilesPath := CreateTestFile(t, "board_*.json", reloadedBoardJSON)
+	defer os.Remove(tilesPath)
+	board, _, err := LoadBoard(tilesPath, "../../test/test_quizzes.json")
+	assert.NoError(t, err)
+	tileMap, err := board.TileMap()
+	assert.NoError(t, err)
+	_, err = g.ReplaceTiles(tileMap)
+	assert.NoError(t, err)
+
+	g.SetBoardVersion(2)
+
+	replaced := g.Board()
+	assert.NotEqual(t, view.Hash, replaced.Hash)
+	assert.Equal(t, 2, replaced.Version)
+	assert.Len(t, replaced.Tiles, 3)
+	assert.JSONEq(t, `{"type": "profit", "amount": 99}`, string(replaced.Tiles[1].Effect))
+	assert.Equal(t, []int{1}, replaced.Tiles[1].PrevIDs)
+	assert.Equal(t, []int{3}, replaced.Tiles[1].NextIDs)
+	assert.Equal(t, &Position{X: 1, Y: 0}, replaced.Tiles[1].Position)
+}
@@ -0,0 +1,166 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func init() {
+	RegisterEffect(sequence, decodeEffect((*SequenceEffect).prepare))
+	RegisterEffect(choice, decodeEffect((*ChoiceEffect).prepare))
+}
+
+// 複数の効果を順番に適用するマス (例: 結婚して30万円もらう)
+type SequenceEffect struct {
+	Effects []json.RawMessage `json:"effects"`
+
+	steps []EffectType
+}
+
+func (e SequenceEffect) RequiresUserInput() bool { return false }
+
+func (e SequenceEffect) GetOptions(tile *Tile, g *Game) any { return nil }
+
+func (e SequenceEffect) Apply(p *Player, g *Game, choice any) error {
+	if e.steps == nil {
+		if err := e.prepare(); err != nil {
+			return err
+		}
+	}
+	for i, step := range e.steps {
+		if err := step.Apply(p, g, nil); err != nil {
+			return fmt.Errorf("effects[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Steps は順番に適用する効果を返す。効果ごとに変化を通知したい場合に使う。
+func (e SequenceEffect) Steps() []EffectType {
+	if e.steps == nil {
+		if err := e.prepare(); err != nil {
+			return nil
+		}
+	}
+	return e.steps
+}
+
+func (e *SequenceEffect) prepare() error {
+	if len(e.Effects) == 0 {
+		return fmt.Errorf("effects is empty")
+	}
+	steps := make([]EffectType, 0, len(e.Effects))
+	for i, raw := range e.Effects {
+		step, err := createCompositeChild(raw)
+		if err != nil {
+			return fmt.Errorf("effects[%d]: %w", i, err)
+		}
+		if step != nil {
+			steps = append(steps, step)
+		}
+	}
+	e.steps = steps
+	return nil
+}
+
+// ChoiceOption は選択マスの選択肢の1つ
+type ChoiceOption struct {
+	Label  string          `json:"label"`
+	Effect json.RawMessage `json:"effect"` // nullの場合は何も起きない
+}
+
+// ChoiceOptionInfo はプレイヤーに送る選択肢
+type ChoiceOptionInfo struct {
+	Index int    `json:"index"`
+	Label string `json:"label"`
+}
+
+// プレイヤーが選択肢の中から1つの効果を選ぶマス (例: 就職するか進学するか)
+type ChoiceEffect struct {
+	Options []ChoiceOption `json:"options"`
+
+	effects []EffectType
+}
+
+func (e ChoiceEffect) RequiresUserInput() bool { return true }
+
+func (e ChoiceEffect) GetOptions(tile *Tile, g *Game) any {
+	options := make([]ChoiceOptionInfo, 0, len(e.Options))
+	for i, o := range e.Options {
+		options = append(options, ChoiceOptionInfo{Index: i, Label: o.Label})
+	}
+	return options
+}
+
+func (e ChoiceEffect) Apply(p *Player, g *Game, choice any) error {
+	effect, err := e.Selected(choice)
+	if err != nil {
+		return err
+	}
+	if effect == nil {
+		return nil
+	}
+	return effect.Apply(p, g, nil)
+}
+
+// Selected は選ばれた選択肢の効果を返す。何も起きない選択肢の場合はnilを返す。
+func (e ChoiceEffect) Selected(choice any) (EffectType, error) {
+	if e.effects == nil {
+		if err := e.prepare(); err != nil {
+			return nil, err
+		}
+	}
+
+	var index int
+	switch v := choice.(type) {
+	case int:
+		index = v
+	case float64:
+		index = int(v)
+	default:
+		return nil, fmt.Errorf("invalid choice for effect choice: unexpected type %T", v)
+	}
+	if index < 0 || index >= len(e.effects) {
+		return nil, fmt.Errorf("invalid choice for effect choice: option %d does not exist", index)
+	}
+	return e.effects[index], nil
+}
+
+func (e *ChoiceEffect) prepare() error {
+	if len(e.Options) < 2 {
+		return fmt.Errorf("choice needs at least 2 options")
+	}
+	effects := make([]EffectType, len(e.Options))
+	for i, o := range e.Options {
+		effect, err := createCompositeChild(o.Effect)
+		if err != nil {
+			return fmt.Errorf("options[%d]: %w", i, err)
+		}
+		effects[i] = effect
+	}
+	e.effects = effects
+	return nil
+}
+
+// tileOnlyEffects はマスに直接置かないと働かない効果。
+// 関所の足止めと判定結果の通知、ゴールの処理は、止まったマスの効果としてしか行われない。
+var tileOnlyEffects = map[TileKind]bool{
+	require: true,
+	goal:    true,
+}
+
+// createCompositeChild は組み合わせの中の効果を生成する。
+// 入力待ちを入れ子にはできないので、プレイヤーの入力が必要な効果はエラーにする。マスに直接置く効果もエラーにする。
+func createCompositeChild(data json.RawMessage) (EffectType, error) {
+	if effectType, err := effectTypeOf(data); err == nil && tileOnlyEffects[effectType] {
+		return nil, fmt.Errorf("effect %q must be placed on a tile and cannot be nested", effectType)
+	}
+	effect, err := createNestedEffect(data)
+	if err != nil {
+		return nil, err
+	}
+	if effect != nil && effect.RequiresUserInput() {
+		return nil, fmt.Errorf("effect %T requires user input and cannot be nested", effect)
+	}
+	return effect, nil
+}
@@ -0,0 +1,71 @@
+package sugoroku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequenceEffect_AppliesInOrder(t *testing.T) {
+	game := NewGameWithTilesForTest("../../tiles.json")
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+
+	effect, err := CreateEffectFromJSON([]byte(`{"type": "sequence", "effects": [
+		{"type": "setStatus", "status": "isMarried", "value": true},
+		{"type": "conditional", "condition": "isMarried", "true_effect": {"type": "profit", "amount": 300000}, "false_effect": null}
+	]}`))
+	assert.NoError(t, err)
+	assert.Len(t, effect.(SequenceEffect).Steps(), 2)
+
+	before := player.Money
+	assert.NoError(t, effect.Apply(player, game, nil))
+	assert.True(t, player.IsMarried)
+	assert.Equal(t, before+300000, player.Money)
+}
+
+func TestChoiceEffect_Selected(t *testing.T) {
+	game := NewGameWithTilesForTest("../../tiles.json")
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+
+	effect, err := CreateEffectFromJSON([]byte(`{"type": "choice", "options": [
+		{"label": "就職する", "effect": {"type": "setStatus", "status": "job", "value": "lecturer"}},
+		{"label": "進学する", "effect": {"type": "loss", "amount": 500000}},
+		{"label": "何もしない", "effect": null}
+	]}`))
+	assert.NoError(t, err)
+	assert.True(t, effect.RequiresUserInput())
+	assert.Equal(t, []ChoiceOptionInfo{
+		{Index: 0, Label: "就職する"},
+		{Index: 1, Label: "進学する"},
+		{Index: 2, Label: "何もしない"},
+	}, effect.GetOptions(nil, game))
+
+	before := player.Money
+	assert.NoError(t, effect.Apply(player, game, float64(1)))
+	assert.Equal(t, before-500000, player.Money)
+	assert.NoError(t, effect.Apply(player, game, 2))
+	assert.Equal(t, before-500000, player.Money)
+
+	assert.Error(t, effect.Apply(player, game, 3))
+	assert.Error(t, effect.Apply(player, game, "0"))
+}
+
+func TestCompositeEffects_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		`{"type": "sequence", "effects": []}`,
+		`{"type": "sequence", "effects": [{"type": "quiz", "quiz_id": 1}]}`,
+		`{"type": "sequence", "effects": [{"type": "require", "mode": "unknown"}]}`,
+		`{"type": "choice", "options": [{"label": "only", "effect": null}]}`,
+		`{"type": "choice", "options": [{"label": "a", "effect": {"type": "gamble"}}, {"label": "b", "effect": null}]}`,
+		// 関所とゴールはマスに直接置かないと働かない
+		`{"type": "sequence", "effects": [{"type": "require", "mode": "min_dice", "require_value": 4}]}`,
+		`{"type": "choice", "options": [{"label": "a", "effect": {"type": "goal"}}, {"label": "b", "effect": null}]}`,
+		`{"type": "lottery", "outcomes": [{"label": "a", "weight": 1, "effect": {"type": "goal"}}]}`,
+		`{"type": "conditional", "condition": "isMarried", "true_effect": {"type": "require", "mode": "min_dice"}}`,
+	} {
+		_, err := CreateEffectFromJSON([]byte(raw))
+		assert.Error(t, err, raw)
+	}
+}
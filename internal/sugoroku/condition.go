@@ -0,0 +1,90 @@
+package sugoroku
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// 条件式で使える変数。以前の "isMarried" などの条件名も、そのまま条件式として使える。
+var conditionVariables = []cel.EnvOption{
+	cel.Variable("money", cel.IntType),          // 所持金
+	cel.Variable("children", cel.IntType),       // 子供の数
+	cel.Variable("isMarried", cel.BoolType),     // 結婚しているか
+	cel.Variable("hasChildren", cel.BoolType),   // 子供がいるか
+	cel.Variable("job", cel.StringType),         // 職業
+	cel.Variable("isProfessor", cel.BoolType),   // 職業がコース長か
+	cel.Variable("isLecturer", cel.BoolType),    // 職業が平教員か
+	cel.Variable("position", cel.IntType),       // 今いるマスのID
+	cel.Variable("playersOnBoard", cel.IntType), // 盤面にいるプレイヤーの数
+}
+
+var (
+	conditionEnv     *cel.Env
+	conditionEnvErr  error
+	conditionEnvOnce sync.Once
+)
+
+func getConditionEnv() (*cel.Env, error) {
+	conditionEnvOnce.Do(func() {
+		conditionEnv, conditionEnvErr = cel.NewEnv(conditionVariables...)
+	})
+	return conditionEnv, conditionEnvErr
+}
+
+// condition はコンパイル済みの条件式
+type condition struct {
+	expr    string
+	program cel.Program
+}
+
+// compileCondition は条件式をコンパイルし、型を検査する。結果がboolにならない式はエラーになる。
+func compileCondition(expr string) (*condition, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("condition is empty")
+	}
+	env, err := getConditionEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create condition env: %w", err)
+	}
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expr, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("condition %q must be bool, got %s", expr, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build condition %q: %w", expr, err)
+	}
+	return &condition{expr: expr, program: program}, nil
+}
+
+// eval はプレイヤーとゲームの状態で条件式を評価する
+func (c *condition) eval(p *Player, g *Game) (bool, error) {
+	p.mu.Lock()
+	vars := map[string]any{
+		"money":       p.Money,
+		"children":    p.HasChildren,
+		"isMarried":   p.IsMarried,
+		"hasChildren": p.HasChildren > 0,
+		"job":         p.Job,
+		"isProfessor": p.Job == JobProfessor,
+		"isLecturer":  p.Job == JobLecturer,
+		"position":    p.Position.Id,
+	}
+	p.mu.Unlock()
+	vars["playersOnBoard"] = len(g.GetAllPlayers())
+
+	out, _, err := c.program.Eval(vars)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", c.expr, err)
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition %q did not return bool", c.expr)
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalEffect_Expressions(t *testing.T) {
+	game := NewGameWithTilesForTest("../../tiles.json")
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+	_, err = game.AddPlayer("p2")
+	assert.NoError(t, err)
+	player.Money = 3000000
+	player.HasChildren = 2
+	player.Job = JobLecturer
+
+	cases := []struct {
+		condition string
+		want      bool
+	}{
+		{`money > 2000000 && children >= 2`, true},
+		{`money > 5000000 || children > 2`, false},
+		{`job == "lecturer"`, true},
+		{`playersOnBoard > 3`, false},
+		{`playersOnBoard == 2`, true},
+		// 以前の条件名もそのまま使える
+		{`hasChildren`, true},
+		{`isMarried`, false},
+		{`isLecturer`, true},
+		{`isProfessor`, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.condition, func(t *testing.T) {
+			data, err := json.Marshal(map[string]any{
+				"type":         "conditional",
+				"condition":    tc.condition,
+				"true_effect":  map[string]any{"type": "profit", "amount": 1},
+				"false_effect": nil,
+			})
+			assert.NoError(t, err)
+			effect, err := CreateEffectFromJSON(data)
+			assert.NoError(t, err)
+
+			before := player.Money
+			assert.NoError(t, effect.Apply(player, game, nil))
+			if tc.want {
+				assert.Equal(t, before+1, player.Money)
+			} else {
+				assert.Equal(t, before, player.Money)
+			}
+		})
+	}
+}
+
+func TestConditionalEffect_InvalidExpressions(t *testing.T) {
+	for _, raw := range []string{
+		`{"type": "conditional", "condition": "money >"}`,
+		`{"type": "conditional", "condition": "salary > 10"}`,
+		`{"type": "conditional", "condition": "money + 1"}`,
+		`{"type": "conditional", "condition": "job == 1"}`,
+		`{"type": "conditional", "condition": ""}`,
+		`{"type": "conditional", "condition": "isMarried", "true_effect": {"type": "require", "mode": "unknown"}}`,
+	} {
+		_, err := CreateEffectFromJSON([]byte(raw))
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestInitTilesFromPath_InvalidCondition(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "conditional", "effect": {"type": "conditional", "condition": "money >> 1"}, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [1], "next_ids": []}
+	]`
+	_, err := InitTilesFromPath(CreateTestFile(t, "condition_tiles_*.json", boardJSON))
+	assert.Error(t, err)
+
+	var tiles []TileJSON
+	assert.NoError(t, json.Unmarshal([]byte(boardJSON), &tiles))
+	assert.Equal(t, []int{1}, issueCodes(ValidateTiles(tiles, nil))["invalid_effect"])
+}
+
+func TestConditionalEffect_RejectsUserInput(t *testing.T) {
+	_, err := CreateEffectFromJSON([]byte(`{"type": "conditional", "condition": "isMarried", "true_effect": {"type": "quiz", "quiz_id": 1}}`))
+	assert.Error(t, err)
+}
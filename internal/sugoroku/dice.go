@@ -0,0 +1,167 @@
+package sugoroku
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// RNG はゲームで使う乱数源
+type RNG interface {
+	Intn(n int) int
+}
+
+// Dice はサイコロ
+type Dice interface {
+	Roll() int
+}
+
+// DiceConfig はサイコロの種類の設定。Fixedが指定された場合は、その順に目を返す。
+type DiceConfig struct {
+	Sides int   `json:"sides"` // 面の数(省略時は6)
+	Count int   `json:"count"` // サイコロの個数(省略時は1)
+	Fixed []int `json:"fixed"` // 検証用に固定で返す目の列
+}
+
+// lockedRNG は複数のgoroutineから使えるようにロックをかけた乱数源。
+// シードから引いた回数を数えておき、復元したときに同じ位置から続けられるようにする。
+type lockedRNG struct {
+	r   *rand.Rand
+	src *countingSource
+	mu  sync.Mutex
+}
+
+func newLockedRNG(seed int64) *lockedRNG {
+	src := &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+	return &lockedRNG{r: rand.New(src), src: src}
+}
+
+func (l *lockedRNG) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+// draws はシードから乱数を引いた回数を返す
+func (l *lockedRNG) draws() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.src.n
+}
+
+// skip は乱数をn回引いて捨て、保存したときと同じ位置まで進める
+func (l *lockedRNG) skip(n int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.src.n < n {
+		l.src.Int63()
+	}
+}
+
+// countingSource は乱数を引いた回数を数える乱数の源
+type countingSource struct {
+	src rand.Source64
+	n   int64
+}
+
+func (s *countingSource) Int63() int64 {
+	s.n++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.n++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.n = 0
+}
+
+// Range は設定したサイコロで出る目の最小値と最大値を返す
+func (cfg DiceConfig) Range() (int, int) {
+	if len(cfg.Fixed) > 0 {
+		lo, hi := cfg.Fixed[0], cfg.Fixed[0]
+		for _, v := range cfg.Fixed[1:] {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		return lo, hi
+	}
+	sides := cfg.Sides
+	if sides == 0 {
+		sides = 6
+	}
+	count := cfg.Count
+	if count == 0 {
+		count = 1
+	}
+	return count, sides * count
+}
+
+// StandardDice は指定した面の数のサイコロを指定した個数振り、その合計を返す
+type StandardDice struct {
+	Sides int
+	Count int
+	rng   RNG
+}
+
+// NewStandardDice はサイコロを生成する
+func NewStandardDice(rng RNG, sides, count int) *StandardDice {
+	return &StandardDice{Sides: sides, Count: count, rng: rng}
+}
+
+func (d *StandardDice) Roll() int {
+	total := 0
+	for i := 0; i < d.Count; i++ {
+		total += d.rng.Intn(d.Sides) + 1
+	}
+	return total
+}
+
+// FixedDice は決められた目を順番に返すサイコロ。最後まで使うと先頭に戻る。
+type FixedDice struct {
+	values []int
+	next   int
+	mu     sync.Mutex
+}
+
+// NewFixedDice は固定の目を返すサイコロを生成する
+func NewFixedDice(values ...int) *FixedDice {
+	return &FixedDice{values: values}
+}
+
+func (d *FixedDice) Roll() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.values) == 0 {
+		return 0
+	}
+	v := d.values[d.next]
+	d.next = (d.next + 1) % len(d.values)
+	return v
+}
+
+// NewDice は設定に応じたサイコロを生成する
+func NewDice(cfg DiceConfig, rng RNG) (Dice, error) {
+	if len(cfg.Fixed) > 0 {
+		for _, v := range cfg.Fixed {
+			if v <= 0 {
+				return nil, errors.New("fixed dice values must be positive")
+			}
+		}
+		return NewFixedDice(cfg.Fixed...), nil
+	}
+	sides := cfg.Sides
+	if sides == 0 {
+		sides = 6
+	}
+	count := cfg.Count
+	if count == 0 {
+		count = 1
+	}
+	if sides < 1 || count < 1 {
+		return nil, errors.New("dice sides and count must be positive")
+	}
+	return NewStandardDice(rng, sides, count), nil
+}
@@ -0,0 +1,56 @@
+package sugoroku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandardDice_Range(t *testing.T) {
+	d := NewStandardDice(newLockedRNG(1), 4, 2)
+	for i := 0; i < 100; i++ {
+		v := d.Roll()
+		assert.GreaterOrEqual(t, v, 2)
+		assert.LessOrEqual(t, v, 8)
+	}
+}
+
+func TestFixedDice_Sequence(t *testing.T) {
+	d := NewFixedDice(3, 1, 6)
+	assert.Equal(t, []int{3, 1, 6, 3}, []int{d.Roll(), d.Roll(), d.Roll(), d.Roll()})
+}
+
+func TestNewDice_Config(t *testing.T) {
+	d, err := NewDice(DiceConfig{}, newLockedRNG(1))
+	assert.NoError(t, err)
+	assert.Equal(t, &StandardDice{Sides: 6, Count: 1, rng: d.(*StandardDice).rng}, d)
+
+	d, err = NewDice(DiceConfig{Fixed: []int{2, 5}}, newLockedRNG(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, d.Roll())
+
+	_, err = NewDice(DiceConfig{Sides: -1}, newLockedRNG(1))
+	assert.Error(t, err)
+	_, err = NewDice(DiceConfig{Fixed: []int{0}}, newLockedRNG(1))
+	assert.Error(t, err)
+}
+
+func TestGame_SameSeedSameRolls(t *testing.T) {
+	g1 := NewGameWithTilesForTest("../../tiles.json")
+	g2 := NewGameWithTilesForTest("../../tiles.json")
+	g1.SetSeed(42)
+	g2.SetSeed(42)
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, g1.RollDice(), g2.RollDice())
+	}
+	assert.Equal(t, int64(42), g1.Seed())
+	assert.Equal(t, GetRandomQuiz(g1.Rand()), GetRandomQuiz(g2.Rand()))
+}
+
+func TestGame_ConfigureDice(t *testing.T) {
+	g := NewGameWithTilesForTest("../../tiles.json")
+	assert.NoError(t, g.ConfigureDice(DiceConfig{Fixed: []int{4}}))
+	assert.Equal(t, 4, g.RollDice())
+	assert.Equal(t, 4, g.RollDice())
+}
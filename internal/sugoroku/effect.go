@@ -4,21 +4,37 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"os"
-	"time"
+	"sync"
 )
 
 type EffectType interface {
 	Apply(player *Player, game *Game, choice any) error // 効果の適用
 	RequiresUserInput() bool                            // ユーザからの入力が必要かどうか
-	GetOptions(tile *Tile) any                          // ユーザの入力の選択肢
+	GetOptions(tile *Tile, g *Game) any                 // ユーザの入力の選択肢
 }
 
 type effectWithType struct {
 	Type TileKind `json:"type"`
 }
 
+func init() {
+	RegisterEffect(profit, decodeEffect[ProfitEffect](nil))
+	RegisterEffect(loss, decodeEffect[LossEffect](nil))
+	RegisterEffect(quiz, decodeEffect[QuizEffect](nil))
+	RegisterEffect(branch, decodeEffect[BranchEffect](nil))
+	RegisterEffect(overall, decodeEffect[OverallEffect](nil))
+	RegisterEffect(neighbor, decodeEffect[NeighborEffect](nil))
+	RegisterEffect(goal, decodeEffect[GoalEffect](nil))
+	RegisterEffect(conditional, decodeEffect((*ConditionalEffect).compile))
+	RegisterEffect(setStatus, decodeEffect[SetStatusEffect](nil))
+	RegisterEffect(childBonus, decodeEffect[ChildBonusEffect](nil))
+	RegisterEffect(advance, decodeEffect[AdvanceEffect](nil))
+	RegisterEffect(retreat, decodeEffect[RetreatEffect](nil))
+	RegisterEffect(warp, decodeEffect[WarpEffect](nil))
+	RegisterEffect(noEffectType, decodeEffect[NoEffect](nil))
+}
+
 // 収入マス
 type ProfitEffect struct {
 	Amount int `json:"amount"`
@@ -26,7 +42,7 @@ type ProfitEffect struct {
 
 func (e ProfitEffect) RequiresUserInput() bool { return false }
 
-func (e ProfitEffect) GetOptions(tile *Tile) any { return nil }
+func (e ProfitEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 // 指定されたお金分増やす
 func (e ProfitEffect) Apply(p *Player, g *Game, choice any) error {
@@ -41,7 +57,7 @@ type LossEffect struct {
 
 func (e LossEffect) RequiresUserInput() bool { return false }
 
-func (e LossEffect) GetOptions(tile *Tile) any { return nil }
+func (e LossEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 // 指定されたお金分減らす
 func (e LossEffect) Apply(p *Player, g *Game, choice any) error {
@@ -63,8 +79,23 @@ type Quiz struct {
 	AnswerDescription string   `json:"answer_description"`
 }
 
-// グローバル変数にキャッシュしておく
-var quizzes []Quiz
+// QuizQuestion はクライアントに送るクイズの問題。答えと解説は回答するまで送らない。
+type QuizQuestion struct {
+	ID       int      `json:"id"`
+	Question string   `json:"question"`
+	Options  []string `json:"options"`
+}
+
+// Public は答えを除いた問題を返す
+func (q Quiz) Public() QuizQuestion {
+	return QuizQuestion{ID: q.ID, Question: q.Question, Options: q.Options}
+}
+
+// グローバル変数にキャッシュしておく。差し替えはSetQuizzesで行う
+var (
+	quizzes []Quiz
+	quizMu  sync.RWMutex
+)
 
 // クイズタイルに必要なオプション
 type QuizEffect struct {
@@ -75,65 +106,110 @@ type QuizEffect struct {
 // クイズマスにユーザからの入力が必要かどうか
 func (e QuizEffect) RequiresUserInput() bool { return true }
 
-// クイズIDからクイズを取ってきて、そのクイズを返す
-func (e QuizEffect) GetOptions(tile *Tile) any {
-	if e.QuizID == 0 {
-		return GetRandomQuiz()
+// 出題するクイズを選び、答えを除いた問題を返す
+func (e QuizEffect) GetOptions(tile *Tile, g *Game) any {
+	quiz := e.Pick(g)
+	if quiz == nil {
+		return nil
 	}
-	for _, quiz := range quizzes {
-		if quiz.ID == e.QuizID {
-			return quiz
+	return quiz.Public()
+}
+
+// Pick は出題するクイズを返す。quiz_idが0の場合はゲームの乱数源を使ってランダムに選ぶ。
+func (e QuizEffect) Pick(g *Game) *Quiz {
+	if e.QuizID == 0 {
+		list := g.Quizzes()
+		if len(list) == 0 {
+			return nil
 		}
+		return &list[g.Rand().Intn(len(list))]
 	}
-	return nil
+	quiz, ok := g.FindQuiz(e.QuizID)
+	if !ok {
+		return nil
+	}
+	return quiz
+}
+
+// QuizAnswer は出題したクイズへの回答。Quizはクライアントではなくサーバーが出題時に記録したもの。
+type QuizAnswer struct {
+	Quiz      Quiz
+	Selection int
+}
+
+// QuizResult はクイズの採点結果
+type QuizResult struct {
+	QuizID            int
+	Selection         int
+	Correct           bool
+	AnswerIndex       int
+	AnswerDescription string
 }
 
 // クイズの実際の処理
 func (e QuizEffect) Apply(p *Player, g *Game, choice any) error {
-	choiceMap, ok := choice.(map[string]interface{})
+	answer, ok := choice.(QuizAnswer)
 	if !ok {
 		return fmt.Errorf("invalid choice for quiz: unexpected type %T", choice)
 	}
+	_, err := e.Answer(p, answer)
+	return err
+}
 
-	quizIDFloat, ok := choiceMap["quizID"].(float64)
-	if !ok {
-		return errors.New("quizID not found or is not a number in choice")
-	}
-	quizID := int(quizIDFloat)
-
-	selectionFloat, ok := choiceMap["selection"].(float64)
-	if !ok {
-		return errors.New("selection not found or is not a number in choice")
-	}
-	selectedOptionIndex := int(selectionFloat)
-
-	var targetQuiz *Quiz
-	for i := range quizzes {
-		if quizzes[i].ID == quizID {
-			targetQuiz = &quizzes[i]
-			break
-		}
+// Answer は回答を採点し、正解なら賞金を与え、不正解なら罰金を取る
+// 出題したときの内容で採点するので、出題後にクイズが差し替えられても結果は変わらない。
+func (e QuizEffect) Answer(p *Player, answer QuizAnswer) (QuizResult, error) {
+	targetQuiz := answer.Quiz
+	if answer.Selection < 0 || answer.Selection >= len(targetQuiz.Options) {
+		return QuizResult{}, fmt.Errorf("selection %d is out of range", answer.Selection)
 	}
 
-	if targetQuiz == nil {
-		return fmt.Errorf("quiz with ID %d not found", e.QuizID)
+	result := QuizResult{
+		QuizID:            targetQuiz.ID,
+		Selection:         answer.Selection,
+		Correct:           answer.Selection == targetQuiz.AnswerIndex,
+		AnswerIndex:       targetQuiz.AnswerIndex,
+		AnswerDescription: targetQuiz.AnswerDescription,
 	}
-
-	if selectedOptionIndex == targetQuiz.AnswerIndex {
+	if result.Correct {
 		p.Profit(e.Amount)
 	} else {
 		p.Loss(e.Amount)
 	}
+	return result, nil
+}
 
-	return nil
+// Forfeit は回答しなかったクイズを不正解として扱い、罰金を取る。Selectionは-1になる。
+func (e QuizEffect) Forfeit(p *Player, quiz Quiz) QuizResult {
+	p.Loss(e.Amount)
+	return QuizResult{
+		QuizID:            quiz.ID,
+		Selection:         -1,
+		AnswerIndex:       quiz.AnswerIndex,
+		AnswerDescription: quiz.AnswerDescription,
+	}
+}
+
+// FindQuiz はIDでクイズを探す
+func FindQuiz(id int) (*Quiz, bool) {
+	quizMu.RLock()
+	defer quizMu.RUnlock()
+	for i := range quizzes {
+		if quizzes[i].ID == id {
+			return &quizzes[i], true
+		}
+	}
+	return nil, false
 }
 
-func GetRandomQuiz() *Quiz {
+// ゲームの乱数源を使ってクイズを1問選ぶ
+func GetRandomQuiz(rng RNG) *Quiz {
+	quizMu.RLock()
+	defer quizMu.RUnlock()
 	if len(quizzes) == 0 {
 		return nil
 	}
-	rand.Seed(time.Now().UnixNano())
-	randomIndex := rand.Intn(len(quizzes))
+	randomIndex := rng.Intn(len(quizzes))
 	return &quizzes[randomIndex]
 }
 
@@ -145,7 +221,7 @@ type BranchEffect struct {
 func (e BranchEffect) RequiresUserInput() bool { return true }
 
 // ユーザの選択肢。次のマスを取得して、それを戻り値にしている。
-func (e BranchEffect) GetOptions(tile *Tile) any {
+func (e BranchEffect) GetOptions(tile *Tile, g *Game) any {
 	options := make([]int, len(tile.nexts))
 	for i, nextTile := range tile.nexts {
 		options[i] = nextTile.Id
@@ -180,13 +256,13 @@ func (e BranchEffect) Apply(p *Player, g *Game, choice any) error {
 	}
 
 	// プレイヤーの位置を選択されたタイルに更新
-	nextTile, exists := g.tileMap[chosenTileID]
-	if !exists {
+	nextTile, err := g.GetTile(chosenTileID)
+	if err != nil {
 		// このエラーは上のバリデーションにより通常発生しないはず
 		return errors.New("chosen tile does not exist")
 	}
 
-	p.Position = nextTile
+	p.moveTo(nextTile)
 	return nil
 }
 
@@ -198,7 +274,7 @@ type OverallEffect struct {
 
 func (e OverallEffect) RequiresUserInput() bool { return false }
 
-func (e OverallEffect) GetOptions(tile *Tile) any { return nil }
+func (e OverallEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 // 　全員にお金を配るもしくはお金をもらう
 func (e OverallEffect) Apply(p *Player, g *Game, choice any) error {
@@ -234,7 +310,7 @@ type NeighborEffect struct {
 
 func (e NeighborEffect) RequiresUserInput() bool { return false }
 
-func (e NeighborEffect) GetOptions(tile *Tile) any { return nil }
+func (e NeighborEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 // 周辺(前後1マス)のプレイヤーからお金をもらうもしくは配る
 func (e NeighborEffect) Apply(p *Player, g *Game, choice any) error {
@@ -253,104 +329,81 @@ func (e NeighborEffect) Apply(p *Player, g *Game, choice any) error {
 	return nil
 }
 
-// 条件分岐
-type RequireEffect struct {
-	RequireValue int `json:"require_value"`
-	Amount       int `json:"amount"`
-}
-
-func (e RequireEffect) RequiresUserInput() bool { return false }
-
-func (e RequireEffect) GetOptions(tile *Tile) any { return nil }
-
-func (e RequireEffect) Apply(p *Player, g *Game, choice any) error {
-
-	return nil
-}
-
-// ギャンブル効果
-type GambleEffect struct {
-}
-
-// ConditionalEffect はプレイヤーのステータスに基づいて異なる効果を適用します。
-
+// ConditionalEffect はプレイヤーやゲームの状態に基づいて異なる効果を適用します。
+// 条件式と中の効果は盤面の読み込み時にまとめて検査される。
 type ConditionalEffect struct {
-	Condition   string          `json:"condition"`    // "isMarried", "hasChildren"
+	Condition   string          `json:"condition"`    // 条件式 (例: "money > 2000000 && children >= 2")
 	TrueEffect  json.RawMessage `json:"true_effect"`  // 条件がtrueの場合の効果
 	FalseEffect json.RawMessage `json:"false_effect"` // 条件がfalseの場合の効果
+
+	cond        *condition
+	trueEffect  EffectType
+	falseEffect EffectType
 }
 
 func (e ConditionalEffect) RequiresUserInput() bool {
-	// TODO: 将来的に、内部のEffectがユーザー入力を要求する可能性を考慮し拡張する
+	// 入力が必要な効果は中に入れられない (createCompositeChild)
 	return false
 }
 
-func (e ConditionalEffect) GetOptions(tile *Tile) any {
+func (e ConditionalEffect) GetOptions(tile *Tile, g *Game) any {
 	return nil
 }
 
 func (e ConditionalEffect) Apply(p *Player, g *Game, choice any) error {
-	var conditionMet bool
-	switch e.Condition {
-	case "isMarried":
-		conditionMet = p.IsMarried
-	case "hasChildren":
-		conditionMet = p.HasChildren > 0
-	case "isProfessor":
-		conditionMet = p.Job == JobProfessor
-	case "isLecturer":
-		conditionMet = p.Job == JobLecturer
-	default:
-		return fmt.Errorf("unknown condition: %s", e.Condition)
+	effect, err := e.Branch(p, g)
+	if err != nil {
+		return err
 	}
-
-	var effectJSON json.RawMessage
-	if conditionMet {
-		effectJSON = e.TrueEffect
-	} else {
-		effectJSON = e.FalseEffect
+	if effect == nil {
+		return nil // 適用する効果がない場合は何もしない
 	}
+	return effect.Apply(p, g, choice)
+}
 
-	if effectJSON == nil || string(effectJSON) == "null" || string(effectJSON) == "{}" {
-		return nil // 適用する効果がない場合は何もしない
+// Branch は条件式を評価し、適用する効果を返す。適用する効果がない場合はnilを返す。
+// 中の効果ごとに変化を通知したい場合に使う。
+func (e ConditionalEffect) Branch(p *Player, g *Game) (EffectType, error) {
+	// CreateEffectFromJSONを通さずに作られた場合は、ここで準備する
+	if e.cond == nil {
+		if err := e.compile(); err != nil {
+			return nil, err
+		}
 	}
 
-	// effectJSONからEffectインスタンスを動的に生成して適用
-	effect, err := CreateEffectFromJSON(effectJSON)
+	conditionMet, err := e.cond.eval(p, g)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	return effect.Apply(p, g, choice)
+	if conditionMet {
+		return e.trueEffect, nil
+	}
+	return e.falseEffect, nil
 }
 
-func (e GambleEffect) RequiresUserInput() bool { return true }
-
-func (e GambleEffect) GetOptions(tile *Tile) any { return nil }
-
-// ギャンブルの入力の有効か検証している
-// 本当はここにギャンブルの処理を書いて、returnでギャンブル結果を返したほうが良いのだろうが、時間がなかったので呼び出し先でギャンブルの判定を行っている。TODO: リファクタリングが必要
-func (e GambleEffect) Apply(p *Player, g *Game, choice any) error {
-	userInput, ok := choice.(map[string]any)
-	if !ok {
-		return errors.New("invalid input format for gamble")
+// compile は条件式をコンパイルし、中の効果を生成する
+func (e *ConditionalEffect) compile() error {
+	cond, err := compileCondition(e.Condition)
+	if err != nil {
+		return err
 	}
+	e.cond = cond
 
-	bet, ok := userInput["bet"].(float64)
-	if !ok {
-		return errors.New("bet is missing or not a number")
+	if e.trueEffect, err = createCompositeChild(e.TrueEffect); err != nil {
+		return fmt.Errorf("true_effect: %w", err)
 	}
-	if int(bet) <= 0 {
-		return errors.New("bet must be positive")
+	if e.falseEffect, err = createCompositeChild(e.FalseEffect); err != nil {
+		return fmt.Errorf("false_effect: %w", err)
 	}
+	return nil
+}
 
-	choiceStr, ok := userInput["choice"].(string)
-	if !ok || (choiceStr != "High" && choiceStr != "Low") {
-		return errors.New("choice must be 'High' or 'Low'")
+// createNestedEffect は組み合わせや条件分岐の中の効果を生成する。効果がない場合はnilを返す。
+func createNestedEffect(data json.RawMessage) (EffectType, error) {
+	if data == nil || string(data) == "null" || string(data) == "{}" {
+		return nil, nil
 	}
-
-	// 検証のみ行い、エラーがなければnilを返す
-	return nil
+	return CreateEffectFromJSON(data)
 }
 
 // 効果なしマス
@@ -359,7 +412,7 @@ type NoEffect struct {
 
 func (e NoEffect) RequiresUserInput() bool { return false }
 
-func (e NoEffect) GetOptions(tile *Tile) any { return nil }
+func (e NoEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 func (e NoEffect) Apply(p *Player, g *Game, choice any) error {
 	return nil
@@ -377,7 +430,7 @@ type SetStatusEffect struct {
 
 func (e SetStatusEffect) RequiresUserInput() bool { return false }
 
-func (e SetStatusEffect) GetOptions(tile *Tile) any { return nil }
+func (e SetStatusEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 func (e SetStatusEffect) Apply(p *Player, g *Game, choice any) error {
 	switch e.Status {
@@ -409,7 +462,7 @@ func (e SetStatusEffect) Apply(p *Player, g *Game, choice any) error {
 
 func (e GoalEffect) RequiresUserInput() bool { return false }
 
-func (e GoalEffect) GetOptions(tile *Tile) any { return nil }
+func (e GoalEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 func (e GoalEffect) Apply(p *Player, g *Game, choice any) error {
 
@@ -424,7 +477,7 @@ type ChildBonusEffect struct {
 
 func (e ChildBonusEffect) RequiresUserInput() bool { return false }
 
-func (e ChildBonusEffect) GetOptions(tile *Tile) any { return nil }
+func (e ChildBonusEffect) GetOptions(tile *Tile, g *Game) any { return nil }
 
 func (e ChildBonusEffect) Apply(p *Player, g *Game, choice any) error {
 	children := p.HasChildren
@@ -443,96 +496,122 @@ func (e ChildBonusEffect) Apply(p *Player, g *Game, choice any) error {
 	return nil
 }
 
-func CreateEffectFromJSON(data json.RawMessage) (EffectType, error) {
-	var ewt effectWithType
-	if err := json.Unmarshal(data, &ewt); err != nil {
-		return nil, fmt.Errorf("effect type unmarshal error: %w", err)
+// 指定したマス数だけ進むマス。途中の分岐やゴールでは止まる。
+type AdvanceEffect struct {
+	Steps int `json:"steps"`
+}
+
+func (e AdvanceEffect) RequiresUserInput() bool { return false }
+
+func (e AdvanceEffect) GetOptions(tile *Tile, g *Game) any { return nil }
+
+func (e AdvanceEffect) Apply(p *Player, g *Game, choice any) error {
+	if e.Steps <= 0 {
+		return errors.New("advance steps must be positive")
 	}
+	p.Move(e.Steps)
+	return nil
+}
+
+// 指定したマス数だけ戻るマス。通ってきた道を逆にたどり、記録がなければprevsの1こ目に戻る。
+type RetreatEffect struct {
+	Steps int `json:"steps"`
+}
+
+func (e RetreatEffect) RequiresUserInput() bool { return false }
 
-	if ewt.Type == "" {
-		return nil, errors.New("effect type is missing")
+func (e RetreatEffect) GetOptions(tile *Tile, g *Game) any { return nil }
+
+func (e RetreatEffect) Apply(p *Player, g *Game, choice any) error {
+	if e.Steps <= 0 {
+		return errors.New("retreat steps must be positive")
 	}
+	p.MoveBack(e.Steps)
+	return nil
+}
 
-	switch ewt.Type {
-	case profit:
-		var profitEffect ProfitEffect
-		if err := json.Unmarshal(data, &profitEffect); err != nil {
-			return nil, fmt.Errorf("ProfitEffect unmarshal error: %w", err)
-		}
-		return profitEffect, nil
-	case loss:
-		var lossEffect LossEffect
-		if err := json.Unmarshal(data, &lossEffect); err != nil {
-			return nil, fmt.Errorf("LossEffect unmarshal error: %w", err)
-		}
-		return lossEffect, nil
-	case quiz:
-		var quizEffect QuizEffect
-		if err := json.Unmarshal(data, &quizEffect); err != nil {
-			return nil, fmt.Errorf("QuizEffect unmarshal error: %w", err)
-		}
-		return quizEffect, nil
-	case branch:
-		var branchEffect BranchEffect
-		if err := json.Unmarshal(data, &branchEffect); err != nil {
-			return nil, fmt.Errorf("BranchEffect unmarshal error: %w", err)
-		}
-		return branchEffect, nil
-	case overall:
-		var overallEffect OverallEffect
-		if err := json.Unmarshal(data, &overallEffect); err != nil {
-			return nil, fmt.Errorf("OverallEffect unmarshal error: %w", err)
-		}
-		return overallEffect, nil
-	case neighbor:
-		var neighborEffect NeighborEffect
-		if err := json.Unmarshal(data, &neighborEffect); err != nil {
-			return nil, fmt.Errorf("NeighborEffect unmarshal error: %w", err)
-		}
-		return neighborEffect, nil
-	case require:
-		var requireEffect RequireEffect
-		if err := json.Unmarshal(data, &requireEffect); err != nil {
-			return nil, fmt.Errorf("RequireEffect unmarshal error: %w", err)
-		}
-		return requireEffect, nil
-	case gamble:
-		return GambleEffect{}, nil
-	case goal:
-		return GoalEffect{}, nil
-	case conditional:
-		var conditionalEffect ConditionalEffect
-		if err := json.Unmarshal(data, &conditionalEffect); err != nil {
-			return nil, fmt.Errorf("ConditionalEffect unmarshal error: %w", err)
-		}
-		return conditionalEffect, nil
-	case setStatus:
-		var setStatusEffect SetStatusEffect
-		if err := json.Unmarshal(data, &setStatusEffect); err != nil {
-			return nil, fmt.Errorf("SetStatusEffect unmarshal error: %w", err)
-		}
-		return setStatusEffect, nil
-	case childBonus:
-		var childBonusEffect ChildBonusEffect
-		if err := json.Unmarshal(data, &childBonusEffect); err != nil {
-			return nil, fmt.Errorf("ChildBonusEffect unmarshal error: %w", err)
+// 指定したマスへワープするマス
+type WarpEffect struct {
+	TileID int `json:"tile_id"`
+}
+
+func (e WarpEffect) RequiresUserInput() bool { return false }
+
+func (e WarpEffect) GetOptions(tile *Tile, g *Game) any { return nil }
+
+func (e WarpEffect) Apply(p *Player, g *Game, choice any) error {
+	tile, err := g.GetTile(e.TileID)
+	if err != nil {
+		return fmt.Errorf("invalid warp destination: %w", err)
+	}
+	p.warpTo(tile)
+	return nil
+}
+
+// SetQuizzes はこのゲームだけで使うクイズを設定する。ジャーナルの再生など、共通のクイズを変えずに別のクイズを使う場合に使う。
+func (g *Game) SetQuizzes(list []Quiz) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.quizzes = list
+}
+
+// Quizzes はゲームで出題するクイズを返す。ゲームだけのクイズを設定していない場合は共通のクイズを返す。
+func (g *Game) Quizzes() []Quiz {
+	g.mu.RLock()
+	list := g.quizzes
+	g.mu.RUnlock()
+	if list == nil {
+		return LoadedQuizzes()
+	}
+	return list
+}
+
+// FindQuiz はゲームで出題するクイズの中からIDでクイズを探す
+func (g *Game) FindQuiz(id int) (*Quiz, bool) {
+	list := g.Quizzes()
+	for i := range list {
+		if list[i].ID == id {
+			return &list[i], true
 		}
-		return childBonusEffect, nil
-	default:
-		return NoEffect{}, nil
 	}
+	return nil, false
 }
 
-func InitQuiz() error {
-	file, err := os.Open(QuizJSONPath)
+// LoadedQuizzes は読み込み済みのクイズを返す。まだ読み込んでいない場合はnilを返す。
+func LoadedQuizzes() []Quiz {
+	quizMu.RLock()
+	defer quizMu.RUnlock()
+	return quizzes
+}
+
+// SetQuizzes はクイズを差し替える。差し替えた後に出題するクイズから新しい内容になる。
+// 出題中のクイズは、出題時に記録したQuizをAnswerに渡すので差し替えの影響を受けない。
+func SetQuizzes(list []Quiz) {
+	quizMu.Lock()
+	defer quizMu.Unlock()
+	quizzes = list
+}
+
+// LoadQuizzes はクイズのJSONを読み込む
+func LoadQuizzes(path string) ([]Quiz, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return fmt.Errorf("file open error: %w", err)
+		return nil, fmt.Errorf("file open error: %w", err)
 	}
 	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&quizzes); err != nil {
-		return fmt.Errorf("JSON decode error: %w", err)
+	var quizList []Quiz
+	if err := json.NewDecoder(file).Decode(&quizList); err != nil {
+		return nil, fmt.Errorf("JSON decode error: %w", err)
+	}
+	return quizList, nil
+}
+
+func InitQuiz() error {
+	quizList, err := LoadQuizzes(QuizJSONPath)
+	if err != nil {
+		return err
 	}
+	SetQuizzes(quizList)
 	return nil
 }
@@ -0,0 +1,223 @@
+package sugoroku
+
+import (
+	"errors"
+	"fmt"
+)
+
+func init() {
+	RegisterEffect(gamble, decodeEffect((*GambleEffect).validate))
+}
+
+// GambleGame はギャンブルマスで遊ぶゲームの種類
+type GambleGame string
+
+const (
+	GambleHighLow         GambleGame = "high_low"          // サイコロの目が基準値以上(High)か未満(Low)かを当てる
+	GambleExact           GambleGame = "exact"             // サイコロの目をぴったり当てる
+	GambleDoubleOrNothing GambleGame = "double_or_nothing" // 選択なしで五分五分。勝てば賭け金が倍、負ければ没収
+)
+
+// GambleHigh, GambleLow はhigh_lowで選べる選択肢
+const (
+	GambleHigh = "High"
+	GambleLow  = "Low"
+)
+
+// ギャンブルマス。ゲームの種類、配当、賭け金の範囲をマスごとに設定できる。
+type GambleEffect struct {
+	Game           GambleGame `json:"game"`            // 省略時はhigh_low
+	ReferenceValue int        `json:"reference_value"` // high_lowの基準値(省略時は3)
+	Payout         float64    `json:"payout"`          // 勝ったときに賭け金の何倍をもらうか(省略時はexactが5、それ以外は1)
+	MinBet         int        `json:"min_bet"`         // 最低賭け金(省略時は1)
+	MaxBet         int        `json:"max_bet"`         // 最高賭け金(0の場合は上限なし)
+	MaxBetPercent  int        `json:"max_bet_percent"` // 所持金のうち賭けられる割合の上限(%)。0の場合は上限なし
+}
+
+// GambleInput はプレイヤーの賭けの内容
+type GambleInput struct {
+	Bet    int
+	Choice any // high_lowは"High"か"Low"、exactは予想する目。double_or_nothingでは使わない
+}
+
+// GambleResult はギャンブルの結果
+type GambleResult struct {
+	Game       GambleGame `json:"game"`
+	Bet        int        `json:"bet"`
+	Choice     any        `json:"choice,omitempty"`
+	DiceResult int        `json:"diceResult,omitempty"` // double_or_nothingでは振らない
+	Won        bool       `json:"won"`
+	Amount     int        `json:"amount"` // 増えた、または減った金額
+	NewMoney   int        `json:"newMoney"`
+}
+
+// GamblePrompt はプレイヤーに賭けを求めるときに送る情報
+type GamblePrompt struct {
+	Game           GambleGame `json:"game"`
+	ReferenceValue int        `json:"referenceValue,omitempty"` // high_lowのときだけ
+	Payout         float64    `json:"payout"`
+	MinBet         int        `json:"minBet"`
+	MaxBet         int        `json:"maxBet"`
+	Options        any        `json:"options,omitempty"`
+}
+
+func (e GambleEffect) RequiresUserInput() bool { return true }
+
+// Prompt はプレイヤーの所持金に応じた賭け金の範囲を含めて、賭けを求めるときに送る情報を返す
+func (e GambleEffect) Prompt(p *Player, g *Game) GamblePrompt {
+	minBet, maxBet := e.BetRange(p)
+	prompt := GamblePrompt{
+		Game:    e.game(),
+		Payout:  e.payout(),
+		MinBet:  minBet,
+		MaxBet:  maxBet,
+		Options: e.GetOptions(p.Position, g),
+	}
+	if e.game() == GambleHighLow {
+		prompt.ReferenceValue = e.referenceValue()
+	}
+	return prompt
+}
+
+// GetOptions は選べる選択肢を返す。double_or_nothingではnilを返す。
+func (e GambleEffect) GetOptions(tile *Tile, g *Game) any {
+	switch e.game() {
+	case GambleHighLow:
+		return []string{GambleHigh, GambleLow}
+	case GambleExact:
+		lo, hi := g.DiceConfig().Range()
+		options := make([]int, 0, hi-lo+1)
+		for v := lo; v <= hi; v++ {
+			options = append(options, v)
+		}
+		return options
+	}
+	return nil
+}
+
+// Apply はmap形式の入力でギャンブルを行う。結果が必要な場合はPlayを使う。
+func (e GambleEffect) Apply(p *Player, g *Game, choice any) error {
+	input, err := ParseGambleInput(choice)
+	if err != nil {
+		return err
+	}
+	_, err = e.Play(p, g, input)
+	return err
+}
+
+// ParseGambleInput はクライアントから送られた {"bet": 数値, "choice": ...} を読み取る
+func ParseGambleInput(choice any) (GambleInput, error) {
+	userInput, ok := choice.(map[string]any)
+	if !ok {
+		return GambleInput{}, errors.New("invalid input format for gamble")
+	}
+	bet, ok := userInput["bet"].(float64)
+	if !ok {
+		return GambleInput{}, errors.New("bet is missing or not a number")
+	}
+	return GambleInput{Bet: int(bet), Choice: userInput["choice"]}, nil
+}
+
+// BetRange はプレイヤーが賭けられる金額の範囲を返す。maxがminより小さい場合は賭けられない。
+func (e GambleEffect) BetRange(p *Player) (int, int) {
+	minBet := e.MinBet
+	if minBet <= 0 {
+		minBet = 1
+	}
+	maxBet := p.Money
+	if e.MaxBet > 0 {
+		maxBet = min(maxBet, e.MaxBet)
+	}
+	if e.MaxBetPercent > 0 {
+		maxBet = min(maxBet, p.Money*e.MaxBetPercent/100)
+	}
+	return minBet, maxBet
+}
+
+// Play は賭け金と選択を検証してからギャンブルを行い、所持金を増減させる
+func (e GambleEffect) Play(p *Player, g *Game, input GambleInput) (GambleResult, error) {
+	minBet, maxBet := e.BetRange(p)
+	if input.Bet < minBet || input.Bet > maxBet {
+		return GambleResult{}, fmt.Errorf("bet must be between %d and %d", minBet, maxBet)
+	}
+
+	result := GambleResult{Game: e.game(), Bet: input.Bet}
+	switch e.game() {
+	case GambleHighLow:
+		choice, ok := input.Choice.(string)
+		if !ok || (choice != GambleHigh && choice != GambleLow) {
+			return GambleResult{}, errors.New("choice must be 'High' or 'Low'")
+		}
+		result.Choice = choice
+		result.DiceResult = g.RollDice()
+		isHigh := result.DiceResult >= e.referenceValue()
+		result.Won = (choice == GambleHigh) == isHigh
+	case GambleExact:
+		guess, ok := input.Choice.(float64)
+		lo, hi := g.DiceConfig().Range()
+		if !ok || int(guess) < lo || int(guess) > hi {
+			return GambleResult{}, fmt.Errorf("choice must be a number between %d and %d", lo, hi)
+		}
+		result.Choice = int(guess)
+		result.DiceResult = g.RollDice()
+		result.Won = result.DiceResult == int(guess)
+	case GambleDoubleOrNothing:
+		result.Won = g.Rand().Intn(2) == 0
+	}
+
+	if result.Won {
+		result.Amount = int(float64(input.Bet) * e.payout())
+		p.Profit(result.Amount)
+	} else {
+		result.Amount = input.Bet
+		p.Loss(result.Amount)
+	}
+	result.NewMoney = p.Money
+	return result, nil
+}
+
+func (e GambleEffect) game() GambleGame {
+	if e.Game == "" {
+		return GambleHighLow
+	}
+	return e.Game
+}
+
+func (e GambleEffect) referenceValue() int {
+	if e.ReferenceValue == 0 {
+		return 3
+	}
+	return e.ReferenceValue
+}
+
+func (e GambleEffect) payout() float64 {
+	if e.Payout > 0 {
+		return e.Payout
+	}
+	if e.game() == GambleExact {
+		return 5
+	}
+	return 1
+}
+
+// validate はマスの設定が正しいかどうかを調べる
+func (e GambleEffect) validate() error {
+	switch e.game() {
+	case GambleHighLow, GambleExact, GambleDoubleOrNothing:
+	default:
+		return fmt.Errorf("unknown gamble game %q", e.Game)
+	}
+	if e.Payout < 0 {
+		return errors.New("payout must not be negative")
+	}
+	if e.MinBet < 0 || e.MaxBet < 0 {
+		return errors.New("bet limits must not be negative")
+	}
+	if e.MaxBet > 0 && e.MinBet > e.MaxBet {
+		return fmt.Errorf("min_bet %d is greater than max_bet %d", e.MinBet, e.MaxBet)
+	}
+	if e.MaxBetPercent < 0 || e.MaxBetPercent > 100 {
+		return fmt.Errorf("max_bet_percent must be between 0 and 100, got %d", e.MaxBetPercent)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGambleEffect_Play(t *testing.T) {
+	cases := []struct {
+		name      string
+		effect    GambleEffect
+		dice      int
+		input     GambleInput
+		wantWon   bool
+		wantMoney int
+	}{
+		{name: "high_low win", effect: GambleEffect{}, dice: 3, input: GambleInput{Bet: 50, Choice: GambleHigh}, wantWon: true, wantMoney: 150},
+		{name: "high_low lose", effect: GambleEffect{}, dice: 2, input: GambleInput{Bet: 50, Choice: GambleHigh}, wantMoney: 50},
+		{name: "high_low reference and payout", effect: GambleEffect{ReferenceValue: 5, Payout: 2}, dice: 4, input: GambleInput{Bet: 10, Choice: GambleLow}, wantWon: true, wantMoney: 120},
+		{name: "exact win", effect: GambleEffect{Game: GambleExact}, dice: 4, input: GambleInput{Bet: 10, Choice: float64(4)}, wantWon: true, wantMoney: 150},
+		{name: "exact lose", effect: GambleEffect{Game: GambleExact}, dice: 4, input: GambleInput{Bet: 10, Choice: float64(5)}, wantMoney: 90},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			game := NewGameWithTilesForTest(CreateTestFile(t, "gamble_tiles_*.json", gateBoardJSON))
+			// exactで選べる目が1〜6になるように、最初に出る目の後に1と6を並べる
+			assert.NoError(t, game.ConfigureDice(DiceConfig{Fixed: []int{tc.dice, 1, 6}}))
+			player, err := game.AddPlayer("p1")
+			assert.NoError(t, err)
+			player.Money = 100
+
+			result, err := tc.effect.Play(player, game, tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantWon, result.Won)
+			assert.Equal(t, tc.dice, result.DiceResult)
+			assert.Equal(t, tc.wantMoney, result.NewMoney)
+			assert.Equal(t, tc.wantMoney, player.Money)
+		})
+	}
+}
+
+func TestGambleEffect_DoubleOrNothing(t *testing.T) {
+	game := NewGameWithTilesForTest(CreateTestFile(t, "gamble_tiles_*.json", gateBoardJSON))
+	game.SetSeed(1)
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+	player.Money = 100
+
+	result, err := GambleEffect{Game: GambleDoubleOrNothing}.Play(player, game, GambleInput{Bet: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, result.Amount)
+	if result.Won {
+		assert.Equal(t, 200, player.Money)
+	} else {
+		assert.Equal(t, 0, player.Money)
+	}
+}
+
+func TestGambleEffect_BetLimits(t *testing.T) {
+	game := NewGameWithTilesForTest(CreateTestFile(t, "gamble_tiles_*.json", gateBoardJSON))
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+	player.Money = 1000
+
+	effect := GambleEffect{MinBet: 10, MaxBet: 500, MaxBetPercent: 20}
+	minBet, maxBet := effect.BetRange(player)
+	assert.Equal(t, 10, minBet)
+	assert.Equal(t, 200, maxBet)
+
+	// 範囲外の賭け金は受け付けず、所持金も変わらない
+	for _, bet := range []int{0, 9, 201} {
+		_, err := effect.Play(player, game, GambleInput{Bet: bet, Choice: GambleHigh})
+		assert.Error(t, err, "bet %d", bet)
+	}
+	assert.Equal(t, 1000, player.Money)
+
+	// 所持金より多くは賭けられない
+	player.Money = 5
+	_, maxBet = GambleEffect{}.BetRange(player)
+	assert.Equal(t, 5, maxBet)
+}
+
+func TestCreateEffectFromJSON_Gamble(t *testing.T) {
+	effect, err := CreateEffectFromJSON(json.RawMessage(`{"type": "gamble"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, GambleEffect{}, effect)
+
+	effect, err = CreateEffectFromJSON(json.RawMessage(`{"type": "gamble", "game": "exact", "payout": 4, "min_bet": 100, "max_bet_percent": 50}`))
+	assert.NoError(t, err)
+	assert.Equal(t, GambleEffect{Game: GambleExact, Payout: 4, MinBet: 100, MaxBetPercent: 50}, effect)
+
+	for _, data := range []string{
+		`{"type": "gamble", "game": "roulette"}`,
+		`{"type": "gamble", "min_bet": 100, "max_bet": 10}`,
+		`{"type": "gamble", "max_bet_percent": 150}`,
+		`{"type": "gamble", "payout": -1}`,
+	} {
+		_, err := CreateEffectFromJSON(json.RawMessage(data))
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,133 @@
+package sugoroku
+
+import "fmt"
+
+func init() {
+	RegisterEffect(require, decodeEffect((*RequireEffect).validate))
+}
+
+// GateMode は関所マスの通過条件の種類
+type GateMode string
+
+const (
+	GateMinDice  GateMode = "min_dice"  // 手番で振ったサイコロの目がrequire_value以上なら通過
+	GateMinMoney GateMode = "min_money" // 所持金がrequire_value以上なら通過
+	GateToll     GateMode = "toll"      // 所持金が通行料amount以上なら払って通過
+)
+
+// GateOnFail は関所の条件を満たせなかったときの扱い
+type GateOnFail string
+
+const (
+	GateHold GateOnFail = "hold" // 関所に足止めされ、次の手番でもう一度挑戦する(min_diceのみ)
+	GatePay  GateOnFail = "pay"  // 罰金amountを払って通過する
+)
+
+// 関所マス。通過するときも止まるときも、ここで一度止まって条件を判定する。
+type RequireEffect struct {
+	Mode         GateMode   `json:"mode"`
+	RequireValue int        `json:"require_value"`
+	Amount       int        `json:"amount"`
+	OnFail       GateOnFail `json:"on_fail"`
+}
+
+// GateResult は関所の判定結果
+type GateResult struct {
+	Mode         GateMode `json:"mode"`
+	RequireValue int      `json:"requireValue"`
+	DiceResult   int      `json:"diceResult,omitempty"` // min_diceのときに判定に使ったサイコロの目
+	Passed       bool     `json:"passed"`
+	Paid         int      `json:"paid"` // 通行料または罰金として払った金額
+	Held         bool     `json:"held"` // 関所に足止めされたかどうか
+}
+
+func (e RequireEffect) RequiresUserInput() bool { return false }
+
+func (e RequireEffect) GetOptions(tile *Tile, g *Game) any { return nil }
+
+// Apply はプレイヤーが最後に振ったサイコロの目で関所の判定を行う。判定結果が必要な場合はCheckを使う。
+func (e RequireEffect) Apply(p *Player, g *Game, choice any) error {
+	_, err := e.Check(p, p.LastRoll())
+	return err
+}
+
+// Check は関所の条件を判定し、通行料や罰金の支払いと足止めを行う。
+// rollはプレイヤーが手番で振ったサイコロの目で、min_diceの判定に使う。
+func (e RequireEffect) Check(p *Player, roll int) (GateResult, error) {
+	result := GateResult{Mode: e.mode(), RequireValue: e.RequireValue}
+
+	switch e.mode() {
+	case GateMinDice:
+		result.DiceResult = roll
+		result.Passed = roll >= e.RequireValue
+	case GateMinMoney:
+		result.Passed = p.Money >= e.RequireValue
+	case GateToll:
+		if p.Money >= e.Amount {
+			if err := p.Loss(e.Amount); err != nil {
+				return result, err
+			}
+			result.Passed = true
+			result.Paid = e.Amount
+		}
+	default:
+		return result, fmt.Errorf("unknown gate mode %q", e.Mode)
+	}
+
+	if !result.Passed {
+		if e.onFail() == GatePay {
+			if err := p.Loss(e.Amount); err != nil {
+				return result, err
+			}
+			result.Paid = e.Amount
+			result.Passed = true
+		} else {
+			p.hold()
+			result.Held = true
+		}
+	}
+	if result.Passed {
+		p.release()
+	}
+	return result, nil
+}
+
+func (e RequireEffect) mode() GateMode {
+	if e.Mode == "" {
+		return GateMinDice
+	}
+	return e.Mode
+}
+
+// onFail は条件を満たせなかったときの扱いを返す。省略時はmin_diceなら足止め、それ以外は支払い。
+// 足止めされている間は所持金が増えないので、min_moneyとtollで足止めするとゴールできなくなる。
+func (e RequireEffect) onFail() GateOnFail {
+	if e.OnFail != "" {
+		return e.OnFail
+	}
+	if e.mode() == GateMinDice {
+		return GateHold
+	}
+	return GatePay
+}
+
+func (e RequireEffect) validate() error {
+	switch e.mode() {
+	case GateMinDice, GateMinMoney, GateToll:
+	default:
+		return fmt.Errorf("unknown gate mode %q", e.Mode)
+	}
+	switch e.onFail() {
+	case GateHold:
+		if e.mode() != GateMinDice {
+			return fmt.Errorf("on_fail %q is only allowed for mode %q", GateHold, GateMinDice)
+		}
+	case GatePay:
+	default:
+		return fmt.Errorf("unknown on_fail %q", e.OnFail)
+	}
+	if e.Amount < 0 {
+		return fmt.Errorf("amount must not be negative")
+	}
+	return nil
+}
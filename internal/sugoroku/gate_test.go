@@ -0,0 +1,110 @@
+package sugoroku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gateBoardJSON = `[
+	{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+	{"id": 2, "kind": "require", "effect": {"type": "require", "mode": "min_dice", "require_value": 4}, "prev_ids": [1], "next_ids": [3]},
+	{"id": 3, "kind": "normal", "prev_ids": [2], "next_ids": [4]},
+	{"id": 4, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [3], "next_ids": []}
+]`
+
+func TestPlayer_MoveStopsAtGate(t *testing.T) {
+	game := NewGameWithTilesForTest(CreateTestFile(t, "gate_tiles_*.json", gateBoardJSON))
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+
+	result := player.Move(3)
+	assert.Equal(t, MoveResult{Stop: "GATE", Remaining: 2}, result)
+	assert.Equal(t, 2, player.Position.Id)
+	assert.Equal(t, 2, player.TakeRemainingSteps())
+	assert.Equal(t, 0, player.TakeRemainingSteps())
+}
+
+func TestRequireEffect_Check(t *testing.T) {
+	cases := []struct {
+		name      string
+		effect    RequireEffect
+		dice      int
+		money     int
+		wantPass  bool
+		wantPaid  int
+		wantHeld  bool
+		wantMoney int
+	}{
+		{name: "min_dice pass", effect: RequireEffect{Mode: GateMinDice, RequireValue: 4}, dice: 5, money: 100, wantPass: true, wantMoney: 100},
+		{name: "min_dice hold", effect: RequireEffect{Mode: GateMinDice, RequireValue: 4}, dice: 3, money: 100, wantHeld: true, wantMoney: 100},
+		{name: "min_dice pay", effect: RequireEffect{Mode: GateMinDice, RequireValue: 4, Amount: 30, OnFail: GatePay}, dice: 3, money: 100, wantPass: true, wantPaid: 30, wantMoney: 70},
+		{name: "min_money pass", effect: RequireEffect{Mode: GateMinMoney, RequireValue: 100}, money: 100, wantPass: true, wantMoney: 100},
+		{name: "min_money pays by default", effect: RequireEffect{Mode: GateMinMoney, RequireValue: 100, Amount: 30}, money: 99, wantPass: true, wantPaid: 30, wantMoney: 69},
+		{name: "toll pass", effect: RequireEffect{Mode: GateToll, Amount: 50}, money: 100, wantPass: true, wantPaid: 50, wantMoney: 50},
+		{name: "toll pays by default", effect: RequireEffect{Mode: GateToll, Amount: 50}, money: 10, wantPass: true, wantPaid: 50, wantMoney: -40},
+		{name: "toll pay", effect: RequireEffect{Mode: GateToll, Amount: 50, OnFail: GatePay}, money: 10, wantPass: true, wantPaid: 50, wantMoney: -40},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			game := NewGameWithTilesForTest(CreateTestFile(t, "gate_tiles_*.json", gateBoardJSON))
+			player, err := game.AddPlayer("p1")
+			assert.NoError(t, err)
+			player.Money = tc.money
+
+			result, err := tc.effect.Check(player, tc.dice)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantPass, result.Passed)
+			assert.Equal(t, tc.wantPaid, result.Paid)
+			assert.Equal(t, tc.wantHeld, result.Held)
+			assert.Equal(t, tc.wantHeld, player.IsHeld())
+			assert.Equal(t, tc.wantMoney, player.Money)
+		})
+	}
+}
+
+func TestRequireEffect_ApplyUsesLastRoll(t *testing.T) {
+	game := NewGameWithTilesForTest(CreateTestFile(t, "gate_tiles_*.json", gateBoardJSON))
+	// 関所用に別のサイコロを振らないことを確かめるため、振ると通過できる目にしておく
+	assert.NoError(t, game.ConfigureDice(DiceConfig{Fixed: []int{6}}))
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+
+	player.RecordRoll(3)
+	assert.NoError(t, RequireEffect{Mode: GateMinDice, RequireValue: 4}.Apply(player, game, nil))
+	assert.True(t, player.IsHeld())
+
+	player.RecordRoll(4)
+	assert.NoError(t, RequireEffect{Mode: GateMinDice, RequireValue: 4}.Apply(player, game, nil))
+	assert.False(t, player.IsHeld())
+}
+
+func TestRequireEffect_HeldPlayerOnMinMoneyGate(t *testing.T) {
+	game := NewGameWithTilesForTest(CreateTestFile(t, "gate_tiles_*.json", gateBoardJSON))
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+	player.Money = 10
+
+	// min_diceの関所で足止めされた後に、盤面の差し替えでmin_moneyの関所に変わった場合も、払って通過できる
+	_, err = RequireEffect{Mode: GateMinDice, RequireValue: 4}.Check(player, 1)
+	assert.NoError(t, err)
+	assert.True(t, player.IsHeld())
+
+	result, err := RequireEffect{Mode: GateMinMoney, RequireValue: 100, Amount: 30}.Check(player, 0)
+	assert.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, 30, result.Paid)
+	assert.False(t, player.IsHeld())
+}
+
+func TestCreateEffectFromJSON_InvalidGate(t *testing.T) {
+	_, err := CreateEffectFromJSON([]byte(`{"type": "require", "mode": "unknown"}`))
+	assert.Error(t, err)
+	_, err = CreateEffectFromJSON([]byte(`{"type": "require", "on_fail": "retry"}`))
+	assert.Error(t, err)
+	// 足止めされると所持金が増えないので、所持金の関所では足止めできない
+	_, err = CreateEffectFromJSON([]byte(`{"type": "require", "mode": "min_money", "require_value": 100, "on_fail": "hold"}`))
+	assert.Error(t, err)
+	_, err = CreateEffectFromJSON([]byte(`{"type": "require", "mode": "toll", "amount": 50, "on_fail": "hold"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package sugoroku
+
+import (
+	"math"
+	"sort"
+)
+
+// Position は盤面上のマスの座標。xはスタートからの列、yはレーン(本線が0で、分岐すると上下に分かれる)
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// layoutTiles はnext_idsのつながりから各マスの座標を計算する。
+// 列はスタートからの最長の段数で、分岐した道は合流するまで別のレーンに並ぶ。
+// 盤面の定義でpositionを指定したマスは、その座標をそのまま使う。
+func layoutTiles(tileMap map[int]*Tile) map[int]Position {
+	ids := make([]int, 0, len(tileMap))
+	for id := range tileMap {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	// スタートから先にたどり、スタートとつながっていないマスは後ろに並べる
+	if _, ok := tileMap[InitialTileID]; ok {
+		ids = append([]int{InitialTileID}, withoutID(ids, InitialTileID)...)
+	}
+
+	// ループするつながり(祖先へ戻る辺)を除いて、前に進む辺だけを残す
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int]int, len(tileMap))
+	forward := make(map[int][]int, len(tileMap))
+	indegree := make(map[int]int, len(tileMap))
+	var visit func(t *Tile)
+	visit = func(t *Tile) {
+		state[t.Id] = visiting
+		for _, next := range t.nexts {
+			if next == nil || state[next.Id] == visiting {
+				continue
+			}
+			if state[next.Id] == unvisited {
+				visit(next)
+			}
+			forward[t.Id] = append(forward[t.Id], next.Id)
+			indegree[next.Id]++
+		}
+		state[t.Id] = visited
+	}
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(tileMap[id])
+		}
+	}
+
+	// 入ってくる辺がなくなった順に並べ、列は最長の段数にする
+	order := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if indegree[id] == 0 {
+			order = append(order, id)
+		}
+	}
+	x := make(map[int]float64, len(ids))
+	for i := 0; i < len(order); i++ {
+		id := order[i]
+		for _, next := range forward[id] {
+			x[next] = math.Max(x[next], x[id]+1)
+			indegree[next]--
+			if indegree[next] == 0 {
+				order = append(order, next)
+			}
+		}
+	}
+
+	// 分岐では親のレーンの幅を分け合い、合流では入ってくるレーンを幅で重み付けした平均に戻る
+	type lane struct{ sumY, sumWidth float64 }
+	incoming := make(map[int]*lane, len(ids))
+	y := make(map[int]float64, len(ids))
+	width := make(map[int]float64, len(ids))
+	for _, id := range order {
+		if in, ok := incoming[id]; ok {
+			y[id] = in.sumY / in.sumWidth
+			width[id] = in.sumWidth
+		} else {
+			width[id] = 1
+		}
+		k := float64(len(forward[id]))
+		for i, next := range forward[id] {
+			in, ok := incoming[next]
+			if !ok {
+				in = &lane{}
+				incoming[next] = in
+			}
+			w := width[id] / k
+			in.sumY += (y[id] + (float64(i)-(k-1)/2)*w) * w
+			in.sumWidth += w
+		}
+	}
+	normalizeLanes(y)
+
+	// 同じ列で同じレーンになったマス(スタートとつながっていない道など)はレーンを1つずつずらす
+	columns := make(map[float64][]int)
+	for _, id := range order {
+		columns[x[id]] = append(columns[x[id]], id)
+	}
+	for _, column := range columns {
+		sort.SliceStable(column, func(i, j int) bool { return y[column[i]] < y[column[j]] })
+		for i := 1; i < len(column); i++ {
+			if prev := y[column[i-1]]; y[column[i]] <= prev {
+				y[column[i]] = prev + 1
+			}
+		}
+	}
+
+	positions := make(map[int]Position, len(ids))
+	for _, id := range ids {
+		if p := tileMap[id].position; p != nil {
+			positions[id] = *p
+			continue
+		}
+		positions[id] = Position{X: x[id], Y: y[id]}
+	}
+	return positions
+}
+
+// normalizeLanes はレーンの間隔が最も狭いところを1になるように広げる
+func normalizeLanes(y map[int]float64) {
+	values := make([]float64, 0, len(y))
+	for _, v := range y {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+	gap := 0.0
+	for i := 1; i < len(values); i++ {
+		if d := values[i] - values[i-1]; d > 1e-9 && (gap == 0 || d < gap) {
+			gap = d
+		}
+	}
+	if gap == 0 {
+		return
+	}
+	for id, v := range y {
+		y[id] = math.Round(v/gap*1e6) / 1e6
+	}
+}
+
+func withoutID(ids []int, id int) []int {
+	kept := make([]int, 0, len(ids))
+	for _, v := range ids {
+		if v != id {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
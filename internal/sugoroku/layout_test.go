@@ -0,0 +1,71 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func layoutFromJSON(t *testing.T, data string) map[int]Position {
+	t.Helper()
+	var tiles []TileJSON
+	assert.NoError(t, json.Unmarshal([]byte(data), &tiles))
+	tileMap, err := buildTiles(tiles)
+	assert.NoError(t, err)
+	return layoutTiles(tileMap)
+}
+
+func TestLayoutTiles_BranchesIntoLanes(t *testing.T) {
+	positions := layoutFromJSON(t, `[
+		{"id": 1, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [], "next_ids": [2, 4]},
+		{"id": 2, "kind": "normal", "effect": null, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "normal", "effect": null, "prev_ids": [2], "next_ids": [5]},
+		{"id": 4, "kind": "normal", "effect": null, "prev_ids": [1], "next_ids": [5]},
+		{"id": 5, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [3, 4], "next_ids": []}
+	]`)
+
+	assert.Equal(t, Position{X: 0, Y: 0}, positions[1])
+	// 分岐した道は別のレーンに並ぶ
+	assert.Equal(t, Position{X: 1, Y: -1}, positions[2])
+	assert.Equal(t, Position{X: 2, Y: -1}, positions[3])
+	assert.Equal(t, Position{X: 1, Y: 1}, positions[4])
+	// 合流するマスは長い方の道の後ろで本線に戻る
+	assert.Equal(t, Position{X: 3, Y: 0}, positions[5])
+}
+
+func TestLayoutTiles_NestedBranches(t *testing.T) {
+	positions := layoutFromJSON(t, `[
+		{"id": 1, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [], "next_ids": [2, 3]},
+		{"id": 2, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [1], "next_ids": [4, 5]},
+		{"id": 3, "kind": "normal", "effect": null, "prev_ids": [1], "next_ids": [6]},
+		{"id": 4, "kind": "normal", "effect": null, "prev_ids": [2], "next_ids": [6]},
+		{"id": 5, "kind": "normal", "effect": null, "prev_ids": [2], "next_ids": [6]},
+		{"id": 6, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [3, 4, 5], "next_ids": []}
+	]`)
+
+	// 分岐の中の分岐は、親のレーンの幅を分け合う
+	assert.Equal(t, -2.0, positions[2].Y)
+	assert.Equal(t, -3.0, positions[4].Y)
+	assert.Equal(t, -1.0, positions[5].Y)
+	assert.Equal(t, 2.0, positions[3].Y)
+	// すべてのレーンが合流すると本線に戻る
+	assert.Equal(t, Position{X: 3, Y: 0}, positions[6])
+}
+
+func TestLayoutTiles_OverridesAndLoops(t *testing.T) {
+	positions := layoutFromJSON(t, `[
+		{"id": 1, "kind": "normal", "effect": null, "prev_ids": [3], "next_ids": [2]},
+		{"id": 2, "kind": "normal", "effect": null, "prev_ids": [1], "position": {"x": 10, "y": 5}, "next_ids": [3]},
+		{"id": 3, "kind": "normal", "effect": null, "prev_ids": [2], "next_ids": [1]},
+		{"id": 4, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [], "next_ids": []}
+	]`)
+
+	// スタートへ戻るつながりがあっても列は前に進む
+	assert.Equal(t, Position{X: 0, Y: 0}, positions[1])
+	assert.Equal(t, Position{X: 2, Y: 0}, positions[3])
+	// 指定した座標がそのまま使われる
+	assert.Equal(t, Position{X: 10, Y: 5}, positions[2])
+	// つながっていないマスは同じ列の別のレーンに置く
+	assert.Equal(t, Position{X: 0, Y: 1}, positions[4])
+}
@@ -0,0 +1,92 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func init() {
+	RegisterEffect(lottery, decodeEffect((*LotteryEffect).prepare))
+}
+
+// LotteryOutcome はくじ引きマスの結果の1つ
+type LotteryOutcome struct {
+	Label  string          `json:"label"`
+	Weight int             `json:"weight"` // 出やすさ。ほかの結果のweightとの比で確率が決まる
+	Effect json.RawMessage `json:"effect"` // nullの場合は何も起きない
+}
+
+// 重み付きの抽選で結果を決め、その結果の効果を適用するマス (例: 株価、天気)
+type LotteryEffect struct {
+	Outcomes []LotteryOutcome `json:"outcomes"`
+
+	effects []EffectType
+}
+
+func (e LotteryEffect) RequiresUserInput() bool { return false }
+
+func (e LotteryEffect) GetOptions(tile *Tile, g *Game) any { return nil }
+
+func (e LotteryEffect) Apply(p *Player, g *Game, choice any) error {
+	index, err := e.Draw(g)
+	if err != nil {
+		return err
+	}
+	effect, _ := e.Outcome(index)
+	if effect == nil {
+		return nil
+	}
+	return effect.Apply(p, g, nil)
+}
+
+// Draw はゲームの乱数で結果を1つ選び、そのインデックスを返す
+func (e LotteryEffect) Draw(g *Game) (int, error) {
+	total := 0
+	for _, o := range e.Outcomes {
+		total += o.Weight
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("lottery has no outcomes")
+	}
+
+	n := g.Rand().Intn(total)
+	for i, o := range e.Outcomes {
+		if n < o.Weight {
+			return i, nil
+		}
+		n -= o.Weight
+	}
+	return len(e.Outcomes) - 1, nil
+}
+
+// Outcome は結果の効果とラベルを返す。何も起きない結果の場合、効果はnilになる。
+func (e LotteryEffect) Outcome(index int) (EffectType, string) {
+	if e.effects == nil {
+		if err := e.prepare(); err != nil {
+			return nil, ""
+		}
+	}
+	if index < 0 || index >= len(e.Outcomes) {
+		return nil, ""
+	}
+	return e.effects[index], e.Outcomes[index].Label
+}
+
+func (e *LotteryEffect) prepare() error {
+	if len(e.Outcomes) == 0 {
+		return fmt.Errorf("outcomes is empty")
+	}
+	effects := make([]EffectType, len(e.Outcomes))
+	for i, o := range e.Outcomes {
+		if o.Weight <= 0 {
+			return fmt.Errorf("outcomes[%d]: weight must be positive", i)
+		}
+		effect, err := createCompositeChild(o.Effect)
+		if err != nil {
+			return fmt.Errorf("outcomes[%d]: %w", i, err)
+		}
+		effects[i] = effect
+	}
+	e.effects = effects
+	return nil
+}
@@ -0,0 +1,66 @@
+package sugoroku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const stockLotteryJSON = `{"type": "lottery", "outcomes": [
+	{"label": "株価が上がった", "weight": 3, "effect": {"type": "profit", "amount": 100}},
+	{"label": "株価が下がった", "weight": 1, "effect": {"type": "loss", "amount": 100}},
+	{"label": "変わらなかった", "weight": 1, "effect": null}
+]}`
+
+func TestLotteryEffect_Draw(t *testing.T) {
+	game := NewGameWithTilesForTest("../../tiles.json")
+	game.SetSeed(1)
+	effect, err := CreateEffectFromJSON([]byte(stockLotteryJSON))
+	assert.NoError(t, err)
+	lottery := effect.(LotteryEffect)
+
+	counts := make([]int, 3)
+	for i := 0; i < 5000; i++ {
+		index, err := lottery.Draw(game)
+		assert.NoError(t, err)
+		counts[index]++
+	}
+	// 重みの比 3:1:1 に近い割合で出る
+	assert.InDelta(t, 3000, counts[0], 200)
+	assert.InDelta(t, 1000, counts[1], 150)
+	assert.InDelta(t, 1000, counts[2], 150)
+
+	outcome, label := lottery.Outcome(1)
+	assert.Equal(t, "株価が下がった", label)
+	assert.Equal(t, LossEffect{Amount: 100}, outcome)
+	outcome, label = lottery.Outcome(2)
+	assert.Nil(t, outcome)
+	assert.Equal(t, "変わらなかった", label)
+}
+
+func TestLotteryEffect_SameSeedSameOutcome(t *testing.T) {
+	effect, err := CreateEffectFromJSON([]byte(stockLotteryJSON))
+	assert.NoError(t, err)
+	lottery := effect.(LotteryEffect)
+
+	g1 := NewGameWithTilesForTest("../../tiles.json")
+	g2 := NewGameWithTilesForTest("../../tiles.json")
+	g1.SetSeed(42)
+	g2.SetSeed(42)
+	for i := 0; i < 20; i++ {
+		a, _ := lottery.Draw(g1)
+		b, _ := lottery.Draw(g2)
+		assert.Equal(t, a, b)
+	}
+}
+
+func TestLotteryEffect_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		`{"type": "lottery", "outcomes": []}`,
+		`{"type": "lottery", "outcomes": [{"label": "a", "weight": 0, "effect": null}]}`,
+		`{"type": "lottery", "outcomes": [{"label": "a", "weight": 1, "effect": {"type": "branch"}}]}`,
+	} {
+		_, err := CreateEffectFromJSON([]byte(raw))
+		assert.Error(t, err, raw)
+	}
+}
@@ -21,6 +21,17 @@ type Player struct {
 	IsMarried   bool
 	HasChildren int
 	Job         string
+	trail       []*Tile // 通ってきたマス。戻る効果はこれを逆にたどる
+	held        bool    // 関所に足止めされているかどうか
+	remaining   int     // 分岐や関所で止まったときの残りの歩数
+	roll        int     // この手番で振ったサイコロの目。関所のmin_diceの判定に使う
+	path        []int   // 前回TakePathを呼んでから通ったマス
+}
+
+// MoveResult はMoveで止まった理由と残りの歩数
+type MoveResult struct {
+	Stop      string // "BRANCH" / "GOAL" / "GATE"。最後まで進んだ場合は空文字
+	Remaining int    // 途中で止まった場合の残りの歩数
 }
 
 // プレイヤーのインスタンスを生成する
@@ -36,28 +47,121 @@ func NewPlayer(id string, position *Tile) *Player {
 // TODO: nextsの1こ目のマスに進むようになっている、ゴールの処理を書かなければならない
 func (p *Player) moveNextTile() {
 	if len(p.Position.nexts) > 0 {
-		p.Position = p.Position.nexts[0]
+		p.moveTo(p.Position.nexts[0])
 	}
 }
 
-// TODO: prevsの1こ目のマスに進むようになっている
+// 通ってきた道を1マス戻る。記録がない場合(ワープ直後や復元直後など)はprevsの1こ目に戻る。
 func (p *Player) movePrevTile() {
+	if n := len(p.trail); n > 0 {
+		p.Position = p.trail[n-1]
+		p.trail = p.trail[:n-1]
+		p.path = append(p.path, p.Position.Id)
+		return
+	}
 	if len(p.Position.prevs) > 0 {
 		p.Position = p.Position.prevs[0]
+		p.path = append(p.path, p.Position.Id)
 	}
+}
+
+// 隣のマスへ進み、今いたマスを通ってきた道として記録する
+func (p *Player) moveTo(tile *Tile) {
+	p.trail = append(p.trail, p.Position)
+	p.Position = tile
+	p.path = append(p.path, tile.Id)
+	p.held = false
+}
+
+// 指定したマスへワープする。道がつながっていないので、通ってきた道の記録は消える。
+func (p *Player) warpTo(tile *Tile) {
+	p.Position = tile
+	p.trail = nil
+	p.path = append(p.path, tile.Id)
+	p.held = false
+}
+
+// 関所に足止めする
+func (p *Player) hold() {
+	p.held = true
+	p.remaining = 0
+}
+
+// 関所の足止めを解除する
+func (p *Player) release() {
+	p.held = false
+}
+
+// IsHeld は関所に足止めされているかどうかを返す
+func (p *Player) IsHeld() bool {
+	return p.held
+}
+
+// RecordRoll は手番で振ったサイコロの目を記録する
+func (p *Player) RecordRoll(n int) {
+	p.roll = n
+}
+
+// LastRoll は最後に振ったサイコロの目を返す
+func (p *Player) LastRoll() int {
+	return p.roll
+}
+
+// TakePath は前回呼んだときから通ったマスのIDを順に返し、記録を消す
+func (p *Player) TakePath() []int {
+	path := p.path
+	p.path = nil
+	return path
+}
 
+// TakeRemainingSteps は分岐や関所で止まったときの残りの歩数を返し、記録を消す
+func (p *Player) TakeRemainingSteps() int {
+	steps := p.remaining
+	p.remaining = 0
+	return steps
 }
 
-// プレイヤーを指定されたマス分移動させるメソッド
-func (p *Player) Move(steps int) string {
+// プレイヤーを指定されたマス分戻すメソッド。分岐やゴールでは止まらない。
+func (p *Player) MoveBack(steps int) {
+	for i := 0; i < steps; i++ {
+		p.movePrevTile()
+	}
+}
+
+// プレイヤーを指定されたマス分移動させるメソッド。
+// 分岐・ゴール・関所のマスでは止まり、残りの歩数を記録しておく。
+func (p *Player) Move(steps int) MoveResult {
+	p.remaining = 0
 	for i := 0; i < steps; i++ {
 		p.moveNextTile()
-		if p.Position.kind == branch {
-			return "BRANCH"
-		} else if p.Position.kind == goal {
-			return "GOAL"
+		if stop := stopReason(p.Position); stop != "" {
+			p.remaining = steps - i - 1
+			return MoveResult{Stop: stop, Remaining: p.remaining}
 		}
 	}
+	return MoveResult{}
+}
+
+// 分岐で道を選んだ後などに、今いるマスから残りの歩数だけ進むメソッド。
+// 今いるマスが分岐・ゴール・関所の場合は、進まずにそのマスで止まる。
+func (p *Player) MoveOn(steps int) MoveResult {
+	if stop := stopReason(p.Position); stop != "" && steps > 0 {
+		p.remaining = steps
+		return MoveResult{Stop: stop, Remaining: steps}
+	}
+	return p.Move(steps)
+}
+
+// stopReason はマスが移動を止めるマスであれば、その理由を返す
+func stopReason(t *Tile) string {
+	switch t.kind {
+	case branch:
+		return "BRANCH"
+	case goal:
+		return "GOAL"
+	case require:
+		return "GATE"
+	}
 	return ""
 }
 
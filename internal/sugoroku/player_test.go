@@ -58,3 +58,68 @@ func TestPlayer_ChangeChildren(t *testing.T) {
 	player.changeChildren(-1)
 	assert.Equal(t, 1, player.HasChildren)
 }
+
+func TestPlayer_MoveBackFollowsTrail(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [1], "next_ids": [3, 4]},
+		{"id": 3, "kind": "normal", "prev_ids": [2], "next_ids": [5]},
+		{"id": 4, "kind": "normal", "prev_ids": [2], "next_ids": [5]},
+		{"id": 5, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [3, 4], "next_ids": []}
+	]`
+	game := NewGameWithTilesForTest(CreateTestFile(t, "trail_tiles_*.json", boardJSON))
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+
+	// 分岐で2つ目の道(マス4)を選んでからゴールまで進む
+	player.Move(1)
+	branchTile := player.Position
+	assert.NoError(t, branchTile.Effect.Apply(player, game, 4))
+	player.Move(1)
+	assert.Equal(t, 5, player.Position.Id)
+
+	// prevsの1こ目(マス3)ではなく、通ってきたマス4を戻る
+	assert.NoError(t, RetreatEffect{Steps: 2}.Apply(player, game, nil))
+	assert.Equal(t, 2, player.Position.Id)
+
+	// ワープすると通ってきた道の記録は消え、prevsの1こ目に戻る
+	assert.NoError(t, WarpEffect{TileID: 5}.Apply(player, game, nil))
+	assert.Equal(t, 5, player.Position.Id)
+	assert.NoError(t, RetreatEffect{Steps: 1}.Apply(player, game, nil))
+	assert.Equal(t, 3, player.Position.Id)
+
+	// 進むマスは分岐やゴールで止まる
+	assert.NoError(t, AdvanceEffect{Steps: 5}.Apply(player, game, nil))
+	assert.Equal(t, 5, player.Position.Id)
+
+	assert.Error(t, WarpEffect{TileID: 99}.Apply(player, game, nil))
+	assert.Error(t, RetreatEffect{}.Apply(player, game, nil))
+}
+
+func TestPlayer_MoveOnAfterBranch(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [1], "next_ids": [3, 4]},
+		{"id": 3, "kind": "normal", "prev_ids": [2], "next_ids": [5]},
+		{"id": 4, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [2], "next_ids": [5]},
+		{"id": 5, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [3, 4], "next_ids": []}
+	]`
+	game := NewGameWithTilesForTest(CreateTestFile(t, "moveon_tiles_*.json", boardJSON))
+	player, err := game.AddPlayer("p1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, MoveResult{Stop: "BRANCH", Remaining: 3}, player.Move(4))
+	assert.Equal(t, 3, player.TakeRemainingSteps())
+	assert.NoError(t, player.Position.Effect.Apply(player, game, 3))
+	assert.Equal(t, MoveResult{Stop: "GOAL", Remaining: 1}, player.MoveOn(2))
+	assert.Equal(t, []int{2, 3, 5}, player.TakePath())
+
+	// 選んだマスが分岐の場合は、そこで止まって残りの歩数を持ち越す
+	player2, err := game.AddPlayer("p2")
+	assert.NoError(t, err)
+	player2.Move(1)
+	assert.NoError(t, player2.Position.Effect.Apply(player2, game, 4))
+	assert.Equal(t, MoveResult{Stop: "BRANCH", Remaining: 2}, player2.MoveOn(2))
+	assert.Equal(t, 4, player2.Position.Id)
+	assert.Equal(t, 2, player2.TakeRemainingSteps())
+}
@@ -0,0 +1,66 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EffectFactory はeffectのJSONから効果を生成する
+type EffectFactory func(data json.RawMessage) (EffectType, error)
+
+// effectFactories はeffect.typeごとの効果の生成方法
+var effectFactories = make(map[TileKind]EffectFactory)
+
+// RegisterEffect はeffect.typeと効果の生成方法を登録する。
+// 新しい種類のマスは、効果を定義したファイルのinitで登録する。同じtypeを2回登録するとpanicする。
+func RegisterEffect(kind TileKind, factory EffectFactory) {
+	if _, dup := effectFactories[kind]; dup {
+		panic(fmt.Sprintf("effect type %q is already registered", kind))
+	}
+	effectFactories[kind] = factory
+}
+
+// IsRegisteredEffect はeffect.typeが登録されているかどうかを返す
+func IsRegisteredEffect(kind TileKind) bool {
+	_, ok := effectFactories[kind]
+	return ok
+}
+
+// decodeEffect はJSONをそのまま効果の構造体に読み込む生成方法を返す。
+// checkを渡すと、読み込んだ後に設定の検査や中の効果の生成を行う。
+func decodeEffect[T EffectType](check func(*T) error) EffectFactory {
+	return func(data json.RawMessage) (EffectType, error) {
+		var e T
+		name := strings.TrimPrefix(fmt.Sprintf("%T", e), "sugoroku.")
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("%s unmarshal error: %w", name, err)
+		}
+		if check != nil {
+			if err := check(&e); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+		return e, nil
+	}
+}
+
+// CreateEffectFromJSON は登録された生成方法でeffectのJSONから効果を生成する。
+// 登録されていないtypeはエラーになる。
+func CreateEffectFromJSON(data json.RawMessage) (EffectType, error) {
+	var ewt effectWithType
+	if err := json.Unmarshal(data, &ewt); err != nil {
+		return nil, fmt.Errorf("effect type unmarshal error: %w", err)
+	}
+
+	if ewt.Type == "" {
+		return nil, errors.New("effect type is missing")
+	}
+
+	factory, ok := effectFactories[ewt.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown effect type %q", ewt.Type)
+	}
+	return factory(data)
+}
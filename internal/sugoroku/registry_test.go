@@ -0,0 +1,36 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateEffectFromJSON_UnknownType(t *testing.T) {
+	_, err := CreateEffectFromJSON(json.RawMessage(`{"type": "teleport"}`))
+	assert.ErrorContains(t, err, `unknown effect type "teleport"`)
+
+	_, err = CreateEffectFromJSON(json.RawMessage(`{"amount": 10}`))
+	assert.ErrorContains(t, err, "effect type is missing")
+}
+
+func TestRegisterEffect(t *testing.T) {
+	const kind TileKind = "test_registry"
+	defer delete(effectFactories, kind)
+
+	assert.False(t, IsRegisteredEffect(kind))
+	RegisterEffect(kind, decodeEffect[ProfitEffect](nil))
+	assert.True(t, IsRegisteredEffect(kind))
+
+	effect, err := CreateEffectFromJSON(json.RawMessage(`{"type": "test_registry", "amount": 7}`))
+	assert.NoError(t, err)
+	assert.Equal(t, ProfitEffect{Amount: 7}, effect)
+
+	assert.Panics(t, func() { RegisterEffect(kind, decodeEffect[LossEffect](nil)) })
+}
+
+func TestCreateEffectFromJSON_CheckError(t *testing.T) {
+	_, err := CreateEffectFromJSON(json.RawMessage(`{"type": "gamble", "game": "roulette"}`))
+	assert.ErrorContains(t, err, "invalid GambleEffect")
+}
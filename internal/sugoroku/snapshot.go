@@ -0,0 +1,129 @@
+package sugoroku
+
+import "fmt"
+
+// PlayerSnapshot は保存用のプレイヤーの状態
+type PlayerSnapshot struct {
+	ID          string `json:"id"`
+	Position    int    `json:"position"`
+	Money       int    `json:"money"`
+	IsMarried   bool   `json:"isMarried"`
+	HasChildren int    `json:"hasChildren"`
+	Job         string `json:"job"`
+	Trail       []int  `json:"trail,omitempty"`
+	Held        bool   `json:"held,omitempty"`
+}
+
+// Snapshot は再起動後にゲームを再開するための状態
+type Snapshot struct {
+	BoardVersion int              `json:"boardVersion,omitempty"` // 盤面の保存先の版の番号。復元するときはこの版の盤面で部屋を作り直す
+	Seed         int64            `json:"seed"`
+	Draws        int64            `json:"draws,omitempty"` // シードから乱数を引いた回数。復元したときは続きの乱数から使う
+	Dice         DiceConfig       `json:"dice"`
+	TurnOrder    []string         `json:"turnOrder"`
+	TurnIndex    int              `json:"turnIndex"`
+	Players      []PlayerSnapshot `json:"players"`
+}
+
+// ゲームの現在の状態を保存用に書き出す
+func (g *Game) Snapshot() Snapshot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	s := Snapshot{
+		BoardVersion: g.boardVersion,
+		Seed:         g.seed,
+		Draws:        g.rngDraws(),
+		Dice:         g.diceConfig,
+		TurnOrder:    append([]string(nil), g.turnOrder...),
+		TurnIndex:    g.turnIndex,
+		Players:      make([]PlayerSnapshot, 0, len(g.players)),
+	}
+	// 参加順に並べておくと、保存したファイルが読みやすい
+	for _, id := range g.turnOrder {
+		if p, ok := g.players[id]; ok {
+			s.Players = append(s.Players, snapshotPlayer(p))
+		}
+	}
+	return s
+}
+
+// rngDraws はゲームの乱数源がシードから乱数を引いた回数を返す。呼び出し側でg.muをロックしておく
+func (g *Game) rngDraws() int64 {
+	if rng, ok := g.rng.(*lockedRNG); ok {
+		return rng.draws()
+	}
+	return 0
+}
+
+func snapshotPlayer(p *Player) PlayerSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	trail := make([]int, 0, len(p.trail))
+	for _, t := range p.trail {
+		trail = append(trail, t.Id)
+	}
+	return PlayerSnapshot{
+		ID:          p.Id,
+		Position:    p.Position.Id,
+		Money:       p.Money,
+		IsMarried:   p.IsMarried,
+		HasChildren: p.HasChildren,
+		Job:         p.Job,
+		Trail:       trail,
+		Held:        p.held,
+	}
+}
+
+// 保存した状態でゲームを置き換える。
+// 乱数はシードから作り直すため、保存前と同じ目の続きにはならない。
+func (g *Game) Restore(s Snapshot) error {
+	players := make(map[string]*Player, len(s.Players))
+	for _, ps := range s.Players {
+		tile, err := g.GetTile(ps.Position)
+		if err != nil {
+			return fmt.Errorf("failed to restore player %s: %w", ps.ID, err)
+		}
+		p := NewPlayer(ps.ID, tile)
+		p.Money = ps.Money
+		p.IsMarried = ps.IsMarried
+		p.HasChildren = ps.HasChildren
+		p.Job = ps.Job
+		p.held = ps.Held
+		for _, id := range ps.Trail {
+			t, err := g.GetTile(id)
+			if err != nil {
+				return fmt.Errorf("failed to restore trail of player %s: %w", ps.ID, err)
+			}
+			p.trail = append(p.trail, t)
+		}
+		players[ps.ID] = p
+	}
+
+	turnOrder := make([]string, 0, len(s.TurnOrder))
+	for _, id := range s.TurnOrder {
+		if _, ok := players[id]; ok {
+			turnOrder = append(turnOrder, id)
+		}
+	}
+	turnIndex := s.TurnIndex
+	if turnIndex < 0 || turnIndex >= len(turnOrder) {
+		turnIndex = 0
+	}
+
+	// 同じ乱数を繰り返さないように、保存したときに引いた回数だけ進めておく
+	g.SetSeed(s.Seed)
+	if rng, ok := g.Rand().(*lockedRNG); ok {
+		rng.skip(s.Draws)
+	}
+	if err := g.ConfigureDice(s.Dice); err != nil {
+		return fmt.Errorf("failed to restore dice: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.players = players
+	g.turnOrder = turnOrder
+	g.turnIndex = turnIndex
+	return nil
+}
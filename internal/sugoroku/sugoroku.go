@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
+	"time"
 )
 
 const InitialTileID = 1
@@ -12,6 +14,19 @@ const InitialTileID = 1
 type Game struct {
 	players map[string]*Player
 	tileMap map[int]*Tile
+	board   BoardView // tileMapから作った、クライアントに返す盤面(Versionはboardの版の番号で埋める)
+
+	boardVersion int // 盤面の保存先の版の番号(保存された版から作っていない場合は0)
+
+	quizzes []Quiz // このゲームだけで使うクイズ(nilの場合は共通のクイズを使う)
+
+	turnOrder []string // 参加順のプレイヤーID
+	turnIndex int      // turnOrderのうち現在手番のプレイヤーの位置
+
+	seed       int64 // 乱数のシード。同じシードと操作列からは同じ結果が再現される
+	rng        RNG
+	dice       Dice
+	diceConfig DiceConfig
 
 	mu sync.RWMutex
 }
@@ -28,12 +43,13 @@ type Game struct {
 //
 
 func NewGame() *Game {
+	return NewGameWithSeed(time.Now().UnixNano())
+}
+
+// シードを指定してゲームを生成する。クイズは起動時にInitQuizで読み込んでおく
+func NewGameWithSeed(seed int64) *Game {
 	tileMap := InitTiles()
-	InitQuiz()
-	return &Game{
-		tileMap: tileMap,
-		players: make(map[string]*Player),
-	}
+	return newGame(tileMap, seed)
 }
 
 // テスト用のラッパー関数
@@ -42,11 +58,39 @@ func NewGameWithTilesForTest(path string) *Game {
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize tiles: %v", err))
 	}
-	InitQuiz()
-	return &Game{
+	return newGame(tileMap, time.Now().UnixNano())
+}
+
+// 指定したパスの盤面からゲームを生成する(ジャーナルの再生などに使う)
+func NewGameFromPath(path string, seed int64) (*Game, error) {
+	tileMap, err := InitTilesFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return newGame(tileMap, seed), nil
+}
+
+// 盤面の定義からゲームを生成する(保存された盤面の版から部屋を作るときなどに使う)
+func NewGameFromTiles(tilesJSON []TileJSON, seed int64) (*Game, error) {
+	issues := ValidateTiles(tilesJSON, LoadedQuizzes())
+	if errs := ErrorIssues(issues); len(errs) > 0 {
+		return nil, &ValidationError{Issues: errs}
+	}
+	tileMap, err := buildTiles(tilesJSON)
+	if err != nil {
+		return nil, err
+	}
+	return newGame(tileMap, seed), nil
+}
+
+func newGame(tileMap map[int]*Tile, seed int64) *Game {
+	g := &Game{
 		tileMap: tileMap,
+		board:   newBoardView(tileMap),
 		players: make(map[string]*Player),
 	}
+	g.SetSeed(seed)
+	return g
 }
 
 //                           __      __                        __
@@ -70,6 +114,7 @@ func (g *Game) AddPlayer(playerID string) (*Player, error) {
 
 	player := NewPlayer(playerID, g.tileMap[InitialTileID])
 	g.players[playerID] = player
+	g.turnOrder = append(g.turnOrder, playerID)
 
 	return player, nil
 }
@@ -80,6 +125,7 @@ func (g *Game) DeletePlayer(playerID string) error {
 
 	if _, exists := g.players[playerID]; exists {
 		delete(g.players, playerID)
+		g.removeFromTurnOrder(playerID)
 		log.Printf("DeletePlayer: %s has deleted", playerID)
 		return nil
 	}
@@ -136,9 +182,124 @@ func (g *Game) GetPlayer(playerID string) (*Player, error) {
 }
 
 func (g *Game) GetTile(tileID int) (*Tile, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	tile, exist := g.tileMap[tileID]
 	if !exist {
 		return nil, fmt.Errorf("tile with id %d does not exist", tileID)
 	}
 	return tile, nil
 }
+
+// 盤面のすべてのマスをID順に返す
+func (g *Game) Tiles() []*Tile {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	tiles := make([]*Tile, 0, len(g.tileMap))
+	for _, tile := range g.tileMap {
+		tiles = append(tiles, tile)
+	}
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].Id < tiles[j].Id })
+	return tiles
+}
+
+// 現在手番のプレイヤーIDを返す。プレイヤーがいない場合は空文字を返す。
+func (g *Game) CurrentTurn() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if len(g.turnOrder) == 0 {
+		return ""
+	}
+	return g.turnOrder[g.turnIndex]
+}
+
+// 手番を次のプレイヤーに進め、新しく手番になったプレイヤーIDを返す
+func (g *Game) AdvanceTurn() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.turnOrder) == 0 {
+		return ""
+	}
+	g.turnIndex = (g.turnIndex + 1) % len(g.turnOrder)
+	return g.turnOrder[g.turnIndex]
+}
+
+// 手番の順番からプレイヤーを取り除く。手番のプレイヤーが抜けた場合は次のプレイヤーに手番が移る。
+func (g *Game) removeFromTurnOrder(playerID string) {
+	for i, id := range g.turnOrder {
+		if id != playerID {
+			continue
+		}
+		g.turnOrder = append(g.turnOrder[:i], g.turnOrder[i+1:]...)
+		if i < g.turnIndex {
+			g.turnIndex--
+		}
+		if g.turnIndex >= len(g.turnOrder) {
+			g.turnIndex = 0
+		}
+		return
+	}
+}
+
+// 乱数のシードを設定し直す。サイコロも標準の6面サイコロに戻る。
+func (g *Game) SetSeed(seed int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.seed = seed
+	g.rng = newLockedRNG(seed)
+	g.dice = NewStandardDice(g.rng, 6, 1)
+	g.diceConfig = DiceConfig{}
+}
+
+// 乱数のシードを返す
+func (g *Game) Seed() int64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.seed
+}
+
+// ゲームの乱数源を返す
+func (g *Game) Rand() RNG {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.rng
+}
+
+// サイコロを差し替える
+func (g *Game) SetDice(d Dice) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.dice = d
+}
+
+// 設定に応じたサイコロに差し替える。サイコロはゲームの乱数源を使う。
+func (g *Game) ConfigureDice(cfg DiceConfig) error {
+	d, err := NewDice(cfg, g.Rand())
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.dice = d
+	g.diceConfig = cfg
+	return nil
+}
+
+// ConfigureDiceで設定したサイコロの設定を返す
+func (g *Game) DiceConfig() DiceConfig {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.diceConfig
+}
+
+// ゲームのサイコロを振る
+func (g *Game) RollDice() int {
+	g.mu.RLock()
+	d := g.dice
+	g.mu.RUnlock()
+	return d.Roll()
+}
@@ -1,6 +1,7 @@
 package sugoroku
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -42,32 +43,108 @@ func TestGame_AddPlayer(t *testing.T) {
 func TestQuizEffect(t *testing.T) {
 	game := NewGameWithTilesForTest("../../tiles.json")
 	player, _ := game.AddPlayer("test_player")
-	player.Money = 0 // Reset money for test
+	player.Money = 100
 
-	quizTile := game.tileMap[9]
-	assert.NotNil(t, quizTile)
-	assert.Equal(t, TileKind("quiz"), quizTile.kind)
+	original := quizzes
+	quizzes = []Quiz{{ID: 1, Question: "1 + 1は？", Options: []string{"1", "2", "3", "4"}, AnswerIndex: 1, AnswerDescription: "答えは2です。"}}
+	t.Cleanup(func() { quizzes = original })
 
-	player.Position = quizTile
+	effect := QuizEffect{QuizID: 1, Amount: 10}
 
-	effect, ok := quizTile.Effect.(QuizEffect)
+	// 出題する問題には答えと解説を含めない
+	question, ok := effect.GetOptions(nil, game).(QuizQuestion)
 	assert.True(t, ok)
+	assert.Equal(t, QuizQuestion{ID: 1, Question: "1 + 1は？", Options: []string{"1", "2", "3", "4"}}, question)
+	data, err := json.Marshal(question)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "answer")
 
-	// Test GetOptions
-	quiz, ok := effect.GetOptions(quizTile).(Quiz)
+	// 正解すると賞金をもらい、採点結果に正解と解説が含まれる
+	quiz, ok := FindQuiz(1)
 	assert.True(t, ok)
-	expectedOptions := []string{"1", "2", "3", "4"}
-	assert.Equal(t, expectedOptions, quiz.Options)
+	result, err := effect.Answer(player, QuizAnswer{Quiz: *quiz, Selection: 1})
+	assert.NoError(t, err)
+	assert.True(t, result.Correct)
+	assert.Equal(t, 1, result.AnswerIndex)
+	assert.Equal(t, "答えは2です。", result.AnswerDescription)
+	assert.Equal(t, 110, player.Money)
+
+	// 不正解だと罰金を払う
+	result, err = effect.Answer(player, QuizAnswer{Quiz: *quiz, Selection: 2})
+	assert.NoError(t, err)
+	assert.False(t, result.Correct)
+	assert.Equal(t, 100, player.Money)
+
+	// 範囲外の選択肢は受け付けない
+	_, err = effect.Answer(player, QuizAnswer{Quiz: *quiz, Selection: 4})
+	assert.Error(t, err)
+	assert.Equal(t, 100, player.Money)
 
-	// Test Apply with correct answer
-	initialMoney := player.Money
-	err := effect.Apply(player, game, 1) // Correct answer index is 1
+	// 出題した後にクイズが差し替えられても、出題したときの内容で採点する
+	quizzes = []Quiz{{ID: 1, Question: "1 + 2は？", Options: []string{"1", "2", "3"}, AnswerIndex: 2}}
+	result, err = effect.Answer(player, QuizAnswer{Quiz: *quiz, Selection: 1})
 	assert.NoError(t, err)
-	assert.Equal(t, initialMoney+10, player.Money)
+	assert.True(t, result.Correct)
+	assert.Equal(t, "答えは2です。", result.AnswerDescription)
+}
 
-	// Test Apply with incorrect answer (no penalty)
-	initialMoney = player.Money
-	err = effect.Apply(player, game, 2) // Incorrect answer
+func TestGame_TurnOrder(t *testing.T) {
+	game := NewGameWithTilesForTest("../../tiles.json")
+	assert.Equal(t, "", game.CurrentTurn())
+
+	game.AddPlayer("p1")
+	game.AddPlayer("p2")
+	game.AddPlayer("p3")
+	assert.Equal(t, "p1", game.CurrentTurn())
+
+	assert.Equal(t, "p2", game.AdvanceTurn())
+	assert.Equal(t, "p3", game.AdvanceTurn())
+	assert.Equal(t, "p1", game.AdvanceTurn())
+
+	// 手番のプレイヤーが抜けると次のプレイヤーの手番になる
+	assert.NoError(t, game.DeletePlayer("p1"))
+	assert.Equal(t, "p2", game.CurrentTurn())
+
+	// 手番より前のプレイヤーが抜けても手番は変わらない
+	game.AdvanceTurn()
+	assert.Equal(t, "p3", game.CurrentTurn())
+	assert.NoError(t, game.DeletePlayer("p2"))
+	assert.Equal(t, "p3", game.CurrentTurn())
+}
+
+func TestGame_SnapshotRestore(t *testing.T) {
+	game := NewGameWithTilesForTest("../../tiles.json")
+	game.SetSeed(7)
+	p1, _ := game.AddPlayer("player1")
+	game.AddPlayer("player2")
+	p1.Move(3)
+	p1.Money = 1234
+	p1.marry()
+	p1.setJob(JobProfessor)
+	game.AdvanceTurn()
+	game.RollDice()
+	game.RollDice()
+
+	snapshot := game.Snapshot()
+	assert.Greater(t, snapshot.Draws, int64(0))
+
+	restored := NewGameWithTilesForTest("../../tiles.json")
+	assert.NoError(t, restored.Restore(snapshot))
+
+	got, err := restored.GetPlayer("player1")
 	assert.NoError(t, err)
-	assert.Equal(t, initialMoney, player.Money)
+	assert.Equal(t, p1.Position.Id, got.Position.Id)
+	assert.Equal(t, 1234, got.Money)
+	assert.True(t, got.IsMarried)
+	assert.Equal(t, JobProfessor, got.Job)
+	assert.Equal(t, "player2", restored.CurrentTurn())
+	assert.Equal(t, int64(7), restored.Seed())
+
+	// 復元したゲームは保存した後の続きの目を出し、最初の目から繰り返さない
+	rolls := []int{game.RollDice(), game.RollDice(), game.RollDice(), game.RollDice()}
+	assert.Equal(t, rolls, []int{restored.RollDice(), restored.RollDice(), restored.RollDice(), restored.RollDice()})
+
+	// 盤面に存在しないマスにいるプレイヤーは復元できない
+	snapshot.Players[0].Position = 99999
+	assert.Error(t, restored.Restore(snapshot))
 }
@@ -3,7 +3,7 @@ package sugoroku
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"log"
 )
 
 const (
@@ -19,6 +19,12 @@ const (
 	conditional TileKind = "conditional"
 	setStatus   TileKind = "setStatus"
 	childBonus  TileKind = "childBonus"
+	advance     TileKind = "advance"
+	retreat     TileKind = "retreat"
+	warp        TileKind = "warp"
+	sequence    TileKind = "sequence"
+	choice      TileKind = "choice"
+	lottery     TileKind = "lottery"
 )
 
 const TilesJSONPath = "./tiles.json"
@@ -32,6 +38,9 @@ type Tile struct {
 	Id     int
 	Effect EffectType
 	detail string
+
+	effectJSON json.RawMessage // 効果の定義(クライアントに盤面を返すときに使う)
+	position   *Position       // 盤面の定義で指定された座標(指定がなければnil)
 }
 
 // JSONの構造に対応した一時的な構造体
@@ -42,6 +51,8 @@ type TileJSON struct {
 	Effect  json.RawMessage `json:"effect"`
 	PrevIDs []int           `json:"prev_ids"`
 	NextIDs []int           `json:"next_ids"`
+	// 画面上の座標。省略すると盤面のつながりから自動で計算する
+	Position *Position `json:"position,omitempty"`
 }
 
 //                                            __                                      __
@@ -67,6 +78,18 @@ func NewTile(prevs []*Tile, nexts []*Tile, kind TileKind, id int, effect EffectT
 	}
 }
 
+// マスの種類を返す
+func (t *Tile) Kind() TileKind { return t.kind }
+
+// マスの説明文を返す
+func (t *Tile) Detail() string { return t.detail }
+
+// 次のマスを返す
+func (t *Tile) Nexts() []*Tile { return t.nexts }
+
+// 前のマスを返す
+func (t *Tile) Prevs() []*Tile { return t.prevs }
+
 //  ______            __    __      __            __  __
 // |      \          |  \  |  \    |  \          |  \|  \
 //  \$$$$$$ _______   \$$ _| $$_    \$$  ______  | $$ \$$ ________   ______
@@ -86,19 +109,25 @@ func InitTiles() map[int]*Tile {
 }
 
 func InitTilesFromPath(path string) (map[int]*Tile, error) {
-	file, err := os.Open(path)
+	tilesJSON, err := LoadTilesJSON(path)
 	if err != nil {
-		return nil, fmt.Errorf("file open error: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	var tilesJSON []TileJSON
-
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&tilesJSON); err != nil {
-		return nil, fmt.Errorf("JSON decode error: %w", err)
+	// 存在しないマスへの参照などはnilのマスになり、移動中に落ちるので読み込みを中止する
+	issues := ValidateTiles(tilesJSON, LoadedQuizzes())
+	if errs := ErrorIssues(issues); len(errs) > 0 {
+		return nil, &ValidationError{Issues: errs}
+	}
+	for _, issue := range issues {
+		log.Printf("board warning: %s", issue)
 	}
 
+	return buildTiles(tilesJSON)
+}
+
+// buildTiles は検証済みの盤面の定義からタイルを生成してつなぐ
+func buildTiles(tilesJSON []TileJSON) (map[int]*Tile, error) {
 	tileMap := make(map[int]*Tile)
 
 	// タイルを生成(この時点ではタイル同士はつながっていない)
@@ -116,6 +145,8 @@ func InitTilesFromPath(path string) (map[int]*Tile, error) {
 		}
 
 		tile := NewTile(nil, nil, tj.Kind, tj.ID, effect, tj.Detail)
+		tile.effectJSON = tj.Effect
+		tile.position = tj.Position
 		tileMap[tile.Id] = tile
 	}
 
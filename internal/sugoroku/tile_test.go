@@ -128,7 +128,7 @@ func TestInitTilesFromPath_AllCases(t *testing.T) {
 		{"id": 6, "kind": "neighbor", "effect": {"type": "neighbor", "amount": 2}, "prev_ids": [4], "next_ids": [7]},
 		{"id": 7, "kind": "require", "effect": {"type": "require", "require_value": 3}, "prev_ids": [5, 6], "next_ids": [8]},
 		{"id": 8, "kind": "gamble", "effect": {"type": "gamble"}, "prev_ids": [7], "next_ids": [9]},
-		{"id": 9, "kind": "unknown", "effect": {"type": "unknown"}, "prev_ids": [8], "next_ids": [10]},
+		{"id": 9, "kind": "normal", "effect": {"type": "no_effect"}, "prev_ids": [8], "next_ids": [10]},
 		{"id": 10, "kind": "none", "effect": null, "prev_ids": [9], "next_ids": []}
 	]`
 	tmpFile := CreateTestFile(t, "all_cases_*.json", allCasesJSON)
@@ -157,7 +157,7 @@ func TestInitTilesFromPath_AllCases(t *testing.T) {
 		t.Errorf("Expected tile 8 to be of kind 'gamble', got '%s'", tileMap[8].kind)
 	}
 	if _, ok := tileMap[9].Effect.(NoEffect); !ok {
-		t.Errorf("Expected tile 9 (unknown type) to have a NoEffect, got %T", tileMap[9].Effect)
+		t.Errorf("Expected tile 9 (no_effect) to have a NoEffect, got %T", tileMap[9].Effect)
 	}
 	if _, ok := tileMap[10].Effect.(NoEffect); !ok {
 		t.Errorf("Expected tile 10 (null effect) to have a NoEffect, got %T", tileMap[10].Effect)
@@ -0,0 +1,358 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Severity は盤面の検証で見つかった問題の重大度
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // 盤面を読み込めない問題
+	SeverityWarning Severity = "warning" // 遊べるが意図していない可能性が高い問題
+)
+
+// normal は効果を持たないマスの種類
+const normal TileKind = "normal"
+
+// noEffectType は効果なしを明示するeffect.type
+const noEffectType TileKind = "no_effect"
+
+// Issue は盤面の検証で見つかった1件の問題
+type Issue struct {
+	Severity Severity `json:"severity"`
+	TileID   int      `json:"tileID"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: tile %d: %s (%s)", i.Severity, i.TileID, i.Message, i.Code)
+}
+
+// ValidationError は盤面に読み込めない問題があったことを表す
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		msgs = append(msgs, issue.String())
+	}
+	return "invalid board: " + strings.Join(msgs, "; ")
+}
+
+// ErrorIssues はissuesのうちエラーのものだけを返す
+func ErrorIssues(issues []Issue) []Issue {
+	var errs []Issue
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// LoadTilesJSON は盤面のJSONをそのまま読み込む(タイル同士はつながない)
+func LoadTilesJSON(path string) ([]TileJSON, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("file open error: %w", err)
+	}
+	defer file.Close()
+
+	var tilesJSON []TileJSON
+	if err := json.NewDecoder(file).Decode(&tilesJSON); err != nil {
+		return nil, fmt.Errorf("JSON decode error: %w", err)
+	}
+	return tilesJSON, nil
+}
+
+// ValidateTiles は盤面の定義を検証し、見つかった問題をマスIDの順に返す。
+// quizListがnilの場合はクイズIDの検証を行わない。
+func ValidateTiles(tiles []TileJSON, quizList []Quiz) []Issue {
+	v := &boardValidator{
+		byID:     make(map[int]TileJSON, len(tiles)),
+		quizList: quizList,
+	}
+	for _, tj := range tiles {
+		if _, dup := v.byID[tj.ID]; dup {
+			v.add(SeverityError, tj.ID, "duplicate_id", "マスIDが重複しています")
+			continue
+		}
+		v.byID[tj.ID] = tj
+	}
+
+	if _, ok := v.byID[InitialTileID]; !ok {
+		v.add(SeverityError, InitialTileID, "missing_start", "スタートのマスがありません")
+	}
+
+	for _, tj := range tiles {
+		v.checkLinks(tj)
+		v.checkEffect(tj)
+	}
+	v.checkReachability()
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		return v.issues[i].TileID < v.issues[j].TileID
+	})
+	return v.issues
+}
+
+type boardValidator struct {
+	byID     map[int]TileJSON
+	quizList []Quiz
+	issues   []Issue
+}
+
+func (v *boardValidator) add(severity Severity, tileID int, code, format string, args ...any) {
+	v.issues = append(v.issues, Issue{
+		Severity: severity,
+		TileID:   tileID,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkLinks は存在しないマスへの参照と、prev/nextの対応の食い違いを調べる
+func (v *boardValidator) checkLinks(tj TileJSON) {
+	for _, id := range tj.NextIDs {
+		next, ok := v.byID[id]
+		if !ok {
+			v.add(SeverityError, tj.ID, "dangling_next", "next_idsのマス%dが存在しません", id)
+			continue
+		}
+		if !containsID(next.PrevIDs, tj.ID) {
+			v.add(SeverityWarning, tj.ID, "link_mismatch", "マス%dのprev_idsにこのマスが含まれていません", id)
+		}
+	}
+	for _, id := range tj.PrevIDs {
+		prev, ok := v.byID[id]
+		if !ok {
+			v.add(SeverityError, tj.ID, "dangling_prev", "prev_idsのマス%dが存在しません", id)
+			continue
+		}
+		if !containsID(prev.NextIDs, tj.ID) {
+			v.add(SeverityWarning, tj.ID, "link_mismatch", "マス%dのnext_idsにこのマスが含まれていません", id)
+		}
+	}
+}
+
+// checkEffect はマスの種類と効果の組み合わせを調べる
+func (v *boardValidator) checkEffect(tj TileJSON) {
+	effectType, err := effectTypeOf(tj.Effect)
+	if err != nil {
+		v.add(SeverityError, tj.ID, "invalid_effect", "effectを読み込めません: %v", err)
+		return
+	}
+	// 未知の効果があると生成に失敗するので、先に調べて未知の効果として報告する
+	before := len(v.issues)
+	v.checkEffectTypes(tj.ID, tj.Effect, false)
+	if len(ErrorIssues(v.issues[before:])) > 0 {
+		return
+	}
+	// 条件式のコンパイルなど、効果の生成時に行われる検査
+	if effectType != "" {
+		if _, err := CreateEffectFromJSON(tj.Effect); err != nil {
+			v.add(SeverityError, tj.ID, "invalid_effect", "effectを読み込めません: %v", err)
+			return
+		}
+	}
+
+	// 条件分岐や組み合わせの中のワープも調べる
+	for _, target := range warpTargets(tj.Effect) {
+		if _, exists := v.byID[target]; !exists {
+			v.add(SeverityError, tj.ID, "dangling_warp", "ワープ先のマス%dが存在しません", target)
+		}
+	}
+
+	if effectType == branch && len(tj.NextIDs) < 2 {
+		v.add(SeverityWarning, tj.ID, "branch_exits", "分岐マスの行き先が%d個しかありません", len(tj.NextIDs))
+	}
+
+	if effectType != "" && !kindMatchesEffect(tj.Kind, effectType) {
+		v.add(SeverityWarning, tj.ID, "kind_mismatch", "kind %q とeffect.type %q が一致しません", tj.Kind, effectType)
+	}
+}
+
+// checkEffectTypes は未知の効果と存在しないクイズを、条件分岐や組み合わせの中の効果も含めて調べる。
+// nestedは条件分岐や組み合わせの中の効果かどうか。
+func (v *boardValidator) checkEffectTypes(tileID int, raw json.RawMessage, nested bool) {
+	effectType, err := effectTypeOf(raw)
+	if err != nil || effectType == "" {
+		return
+	}
+	if !IsRegisteredEffect(effectType) {
+		v.add(SeverityError, tileID, "unknown_effect", "未知のeffect.type %q です", effectType)
+		return
+	}
+	if nested && tileOnlyEffects[effectType] {
+		v.add(SeverityError, tileID, "nested_effect", "effect.type %q は組み合わせの中に入れられません", effectType)
+		return
+	}
+
+	if effectType == quiz {
+		var e QuizEffect
+		if err := json.Unmarshal(raw, &e); err == nil && e.QuizID != 0 && v.quizList != nil && !hasQuiz(v.quizList, e.QuizID) {
+			v.add(SeverityWarning, tileID, "missing_quiz", "quiz_id %d のクイズが存在しません", e.QuizID)
+		}
+	}
+	for _, child := range childEffects(effectType, raw) {
+		v.checkEffectTypes(tileID, child, true)
+	}
+}
+
+// childEffects は条件分岐や組み合わせの中の効果を返す。中に効果を持たない効果の場合はnilを返す。
+func childEffects(effectType TileKind, raw json.RawMessage) []json.RawMessage {
+	var children []json.RawMessage
+	switch effectType {
+	case conditional:
+		var e ConditionalEffect
+		if err := json.Unmarshal(raw, &e); err == nil {
+			children = append(children, e.TrueEffect, e.FalseEffect)
+		}
+	case sequence:
+		var e SequenceEffect
+		if err := json.Unmarshal(raw, &e); err == nil {
+			children = append(children, e.Effects...)
+		}
+	case choice:
+		var e ChoiceEffect
+		if err := json.Unmarshal(raw, &e); err == nil {
+			for _, o := range e.Options {
+				children = append(children, o.Effect)
+			}
+		}
+	case lottery:
+		var e LotteryEffect
+		if err := json.Unmarshal(raw, &e); err == nil {
+			for _, o := range e.Outcomes {
+				children = append(children, o.Effect)
+			}
+		}
+	}
+	return children
+}
+
+// checkReachability はスタートから辿れないマス、行き止まり、ゴールに辿り着けないマスを調べる
+func (v *boardValidator) checkReachability() {
+	if _, ok := v.byID[InitialTileID]; !ok {
+		return
+	}
+
+	// ワープでしか行けないマスもあるので、ワープ先も行き先として扱う
+	exits := func(tj TileJSON) []int {
+		if targets := warpTargets(tj.Effect); len(targets) > 0 {
+			return append(append([]int(nil), tj.NextIDs...), targets...)
+		}
+		return tj.NextIDs
+	}
+	reachable := v.walk([]int{InitialTileID}, exits)
+
+	// ゴールから逆向きに辿り、ゴールに行けるマスを求める
+	var goals []int
+	for id, tj := range v.byID {
+		if t, err := effectTypeOf(tj.Effect); err == nil && t == goal {
+			goals = append(goals, id)
+		}
+	}
+	prevsOf := make(map[int][]int)
+	for id, tj := range v.byID {
+		for _, next := range exits(tj) {
+			prevsOf[next] = append(prevsOf[next], id)
+		}
+	}
+	canFinish := v.walk(goals, func(tj TileJSON) []int { return prevsOf[tj.ID] })
+
+	for id, tj := range v.byID {
+		isGoal := containsID(goals, id)
+		switch {
+		case !reachable[id]:
+			v.add(SeverityWarning, id, "unreachable", "スタートから辿り着けません")
+		case len(exits(tj)) == 0 && !isGoal:
+			v.add(SeverityWarning, id, "dead_end", "ゴールではない行き止まりです")
+		case !canFinish[id]:
+			v.add(SeverityWarning, id, "no_goal", "このマスからゴールに辿り着けません")
+		}
+	}
+}
+
+// walk はstartから辿れるマスの集合を返す
+func (v *boardValidator) walk(start []int, neighbors func(TileJSON) []int) map[int]bool {
+	visited := make(map[int]bool)
+	queue := append([]int(nil), start...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		tj, ok := v.byID[id]
+		if !ok || visited[id] {
+			continue
+		}
+		visited[id] = true
+		queue = append(queue, neighbors(tj)...)
+	}
+	return visited
+}
+
+// warpTargets は効果に含まれるワープのワープ先のマスIDを、条件分岐や組み合わせの中のワープも含めて返す
+func warpTargets(raw json.RawMessage) []int {
+	effectType, err := effectTypeOf(raw)
+	if err != nil {
+		return nil
+	}
+	if effectType == warp {
+		var e WarpEffect
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil
+		}
+		return []int{e.TileID}
+	}
+	var targets []int
+	for _, child := range childEffects(effectType, raw) {
+		targets = append(targets, warpTargets(child)...)
+	}
+	return targets
+}
+
+// effectTypeOf はeffectのtypeを返す。効果が省略されている場合は空文字を返す。
+func effectTypeOf(raw json.RawMessage) (TileKind, error) {
+	if len(raw) == 0 || string(raw) == "null" || string(raw) == "{}" {
+		return "", nil
+	}
+	var ewt effectWithType
+	if err := json.Unmarshal(raw, &ewt); err != nil {
+		return "", err
+	}
+	return ewt.Type, nil
+}
+
+// kindMatchesEffect はマスの種類と効果の組み合わせが妥当かどうかを返す
+func kindMatchesEffect(kind TileKind, effectType TileKind) bool {
+	if effectType == noEffectType {
+		return kind == normal || kind == ""
+	}
+	return kind == effectType
+}
+
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasQuiz(quizList []Quiz, id int) bool {
+	for _, q := range quizList {
+		if q.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package sugoroku
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func issueCodes(issues []Issue) map[string][]int {
+	codes := make(map[string][]int)
+	for _, issue := range issues {
+		codes[issue.Code] = append(codes[issue.Code], issue.TileID)
+	}
+	return codes
+}
+
+func TestValidateTiles_Issues(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "effect": {"type": "no_effect"}, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "branch", "effect": {"type": "branch"}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "profit", "effect": {"type": "loss", "amount": 10}, "prev_ids": [2], "next_ids": [4, 5]},
+		{"id": 4, "kind": "quiz", "effect": {"type": "quiz", "quiz_id": 999}, "prev_ids": [3], "next_ids": [6]},
+		{"id": 5, "kind": "normal", "effect": {"type": "no_effect"}, "prev_ids": [], "next_ids": []},
+		{"id": 6, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [4], "next_ids": []},
+		{"id": 7, "kind": "normal", "effect": null, "prev_ids": [], "next_ids": [6]}
+	]`
+	var tiles []TileJSON
+	assert.NoError(t, json.Unmarshal([]byte(boardJSON), &tiles))
+
+	issues := ValidateTiles(tiles, []Quiz{{ID: 1}})
+	assert.Empty(t, ErrorIssues(issues))
+
+	codes := issueCodes(issues)
+	assert.Equal(t, []int{2}, codes["branch_exits"])
+	assert.Equal(t, []int{3}, codes["kind_mismatch"])
+	assert.Equal(t, []int{3, 7}, codes["link_mismatch"])
+	assert.Equal(t, []int{4}, codes["missing_quiz"])
+	assert.Equal(t, []int{5}, codes["dead_end"])
+	assert.Equal(t, []int{7}, codes["unreachable"])
+}
+
+func TestValidateTiles_Errors(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2, 99]},
+		{"id": 2, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [1, 98], "next_ids": []},
+		{"id": 2, "kind": "normal", "prev_ids": [], "next_ids": []},
+		{"id": 3, "kind": "warp", "effect": {"type": "warp", "tile_id": 42}, "prev_ids": [], "next_ids": []},
+		{"id": 4, "kind": "teleport", "effect": {"type": "teleport"}, "prev_ids": [], "next_ids": []},
+		{"id": 5, "kind": "sequence", "effect": {"type": "sequence", "effects": [{"type": "profit", "amount": 1}, {"type": "teleport"}]}, "prev_ids": [], "next_ids": []}
+	]`
+	var tiles []TileJSON
+	assert.NoError(t, json.Unmarshal([]byte(boardJSON), &tiles))
+
+	codes := issueCodes(ErrorIssues(ValidateTiles(tiles, nil)))
+	assert.Equal(t, []int{1}, codes["dangling_next"])
+	assert.Contains(t, codes["dangling_prev"], 2)
+	assert.Equal(t, []int{2}, codes["duplicate_id"])
+	assert.Equal(t, []int{3}, codes["dangling_warp"])
+	assert.Equal(t, []int{4, 5}, codes["unknown_effect"])
+	assert.Empty(t, codes["invalid_effect"])
+}
+
+func TestValidateTiles_NestedWarps(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "sequence", "effect": {"type": "sequence", "effects": [{"type": "profit", "amount": 1}, {"type": "warp", "tile_id": 41}]}, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "conditional", "effect": {"type": "conditional", "condition": "isMarried", "false_effect": {"type": "warp", "tile_id": 42}}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "choice", "effect": {"type": "choice", "options": [{"label": "a", "effect": null}, {"label": "b", "effect": {"type": "warp", "tile_id": 43}}]}, "prev_ids": [2], "next_ids": [4]},
+		{"id": 4, "kind": "lottery", "effect": {"type": "lottery", "outcomes": [{"label": "a", "weight": 1, "effect": {"type": "warp", "tile_id": 44}}]}, "prev_ids": [3], "next_ids": [5]},
+		{"id": 5, "kind": "sequence", "effect": {"type": "sequence", "effects": [{"type": "warp", "tile_id": 7}]}, "prev_ids": [4], "next_ids": [6]},
+		{"id": 6, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [5], "next_ids": []},
+		{"id": 7, "kind": "normal", "prev_ids": [], "next_ids": [6]}
+	]`
+	var tiles []TileJSON
+	assert.NoError(t, json.Unmarshal([]byte(boardJSON), &tiles))
+
+	// 組み合わせや条件分岐の中のワープ先も調べる
+	issues := ValidateTiles(tiles, nil)
+	assert.Equal(t, []int{1, 2, 3, 4}, issueCodes(ErrorIssues(issues))["dangling_warp"])
+	// 中のワープでしか行けないマスも辿り着けるマスとして扱う
+	assert.NotContains(t, issueCodes(issues)["unreachable"], 7)
+}
+
+func TestValidateTiles_NestedTileOnlyEffects(t *testing.T) {
+	const boardJSON = `[
+		{"id": 1, "kind": "sequence", "effect": {"type": "sequence", "effects": [{"type": "require", "mode": "min_dice", "require_value": 4}]}, "prev_ids": [], "next_ids": [2]},
+		{"id": 2, "kind": "conditional", "effect": {"type": "conditional", "condition": "isMarried", "true_effect": {"type": "goal"}}, "prev_ids": [1], "next_ids": [3]},
+		{"id": 3, "kind": "lottery", "effect": {"type": "lottery", "outcomes": [{"label": "a", "weight": 1, "effect": {"type": "require", "mode": "toll", "amount": 10}}]}, "prev_ids": [2], "next_ids": [4]},
+		{"id": 4, "kind": "choice", "effect": {"type": "choice", "options": [{"label": "a", "effect": {"type": "goal"}}, {"label": "b", "effect": null}]}, "prev_ids": [3], "next_ids": [5]},
+		{"id": 5, "kind": "goal", "effect": {"type": "goal"}, "prev_ids": [4], "next_ids": []}
+	]`
+	var tiles []TileJSON
+	assert.NoError(t, json.Unmarshal([]byte(boardJSON), &tiles))
+
+	// 関所とゴールは組み合わせや条件分岐の中に置けない
+	codes := issueCodes(ErrorIssues(ValidateTiles(tiles, nil)))
+	assert.Equal(t, []int{1, 2, 3, 4}, codes["nested_effect"])
+	assert.Empty(t, codes["invalid_effect"])
+}
+
+func TestInitTilesFromPath_DanglingID(t *testing.T) {
+	const danglingJSON = `[{"id": 1, "kind": "normal", "prev_ids": [], "next_ids": [2]}]`
+	tmpFile := CreateTestFile(t, "dangling_*.json", danglingJSON)
+	defer os.Remove(tmpFile)
+
+	_, err := InitTilesFromPath(tmpFile)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "dangling_next", validationErr.Issues[0].Code)
+}
+
+func TestInitTilesFromPath_UnknownEffect(t *testing.T) {
+	const unknownJSON = `[{"id": 1, "kind": "teleport", "effect": {"type": "teleport"}, "prev_ids": [], "next_ids": []}]`
+	tmpFile := CreateTestFile(t, "unknown_*.json", unknownJSON)
+	defer os.Remove(tmpFile)
+
+	_, err := InitTilesFromPath(tmpFile)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "unknown_effect", validationErr.Issues[0].Code)
+}
+
+func TestValidateTiles_DefaultBoard(t *testing.T) {
+	tiles, err := LoadTilesJSON("../../tiles.json")
+	assert.NoError(t, err)
+	assert.Empty(t, ErrorIssues(ValidateTiles(tiles, LoadedQuizzes())))
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,9 +10,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/shii-park/Metasugo-Backend/internal/boardstore"
 	"github.com/shii-park/Metasugo-Backend/internal/game"
 	"github.com/shii-park/Metasugo-Backend/internal/handler"
 	"github.com/shii-park/Metasugo-Backend/internal/hub"
+	"github.com/shii-park/Metasugo-Backend/internal/room"
 	"github.com/shii-park/Metasugo-Backend/internal/sugoroku"
 )
 
@@ -238,3 +241,185 @@ func TestHandleWebSocket_CloseConnection(t *testing.T) {
 
 	t.Log("WebSocket接続を正常にクローズしました")
 }
+
+// RoomHandlerのテスト: 盤面の図をDOT形式とMermaid形式で取得する
+func TestGetBoardGraph(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rooms := room.NewRegistry(func() *sugoroku.Game {
+		return sugoroku.NewGameWithTilesForTest("test_tiles.json")
+	})
+	if _, err := rooms.Create("booth-a", room.Options{}); err != nil {
+		t.Fatalf("部屋の作成に失敗: %v", err)
+	}
+	roomHandler := handler.NewRoomHandler(rooms)
+
+	router := gin.New()
+	router.GET("/rooms/:roomID/board/graph", roomHandler.GetBoardGraph)
+
+	cases := []struct {
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"", http.StatusOK, "digraph board {"},
+		{"?format=mermaid", http.StatusOK, "flowchart LR"},
+		{"?format=svg", http.StatusBadRequest, "format"},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest("GET", "/rooms/booth-a/board/graph"+tc.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s: 期待されるステータスコード: %d, 実際: %d", tc.query, tc.wantStatus, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), tc.wantBody) {
+			t.Errorf("%s: レスポンスに %q が含まれていません: %s", tc.query, tc.wantBody, w.Body.String())
+		}
+	}
+
+	// 存在しない部屋は404
+	req, _ := http.NewRequest("GET", "/rooms/unknown/board/graph", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("期待されるステータスコード: %d, 実際: %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// RoomHandlerのテスト: 盤面を取得し、ETagが一致する場合は304を返す
+func TestGetBoard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rooms := room.NewRegistry(func() *sugoroku.Game {
+		return sugoroku.NewGameWithTilesForTest("test_tiles.json")
+	})
+	r, err := rooms.Create("booth-a", room.Options{})
+	if err != nil {
+		t.Fatalf("部屋の作成に失敗: %v", err)
+	}
+	roomHandler := handler.NewRoomHandler(rooms)
+
+	router := gin.New()
+	router.GET("/rooms/:roomID/board", roomHandler.GetBoard)
+
+	req, _ := http.NewRequest("GET", "/rooms/booth-a/board", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("期待されるステータスコード: %d, 実際: %d", http.StatusOK, w.Code)
+	}
+	var view sugoroku.BoardView
+	if err := json.Unmarshal(w.Body.Bytes(), &view); err != nil {
+		t.Fatalf("レスポンスの解析に失敗: %v", err)
+	}
+	if view.Hash != r.Game.Board().Hash || len(view.Tiles) != len(r.Game.Tiles()) {
+		t.Errorf("部屋のゲームの盤面と一致しません: %+v", view)
+	}
+	etag := w.Header().Get("ETag")
+	if etag != `"`+view.Hash+`"` {
+		t.Errorf("ETagが盤面のハッシュと一致しません: %s", etag)
+	}
+
+	// 同じ版を持っている場合は本文を返さない
+	req, _ = http.NewRequest("GET", "/rooms/booth-a/board", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("期待されるステータスコード: %d, 実際: %d", http.StatusNotModified, w.Code)
+	}
+
+	// 存在しない部屋は404
+	req, _ = http.NewRequest("GET", "/rooms/unknown/board", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("期待されるステータスコード: %d, 実際: %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// AdminHandlerのテスト: 盤面の編集と版の操作
+func TestAdminBoardAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tiles, err := sugoroku.LoadTilesJSON("test_tiles.json")
+	if err != nil {
+		t.Fatalf("盤面の読み込みに失敗: %v", err)
+	}
+	boards, err := boardstore.Open(t.TempDir(), tiles)
+	if err != nil {
+		t.Fatalf("盤面の保存先の初期化に失敗: %v", err)
+	}
+	rooms := room.NewRegistry(func() *sugoroku.Game {
+		return sugoroku.NewGameWithTilesForTest("test_tiles.json")
+	})
+	rooms.SetBoardStore(boards)
+	adminHandler := handler.NewAdminHandler(rooms, boards)
+
+	router := gin.New()
+	admin := router.Group("/admin", func(c *gin.Context) { c.Set("firebase_uid", "admin-user") })
+	admin.GET("/board/versions", adminHandler.ListVersions)
+	admin.GET("/board/versions/:version", adminHandler.GetVersion)
+	admin.POST("/board/versions/:version/rollback", adminHandler.RollbackVersion)
+	admin.PUT("/board/active", adminHandler.SetActiveVersion)
+	admin.GET("/board/tiles", adminHandler.ListTiles)
+	admin.POST("/board/tiles", adminHandler.CreateTile)
+	admin.GET("/board/tiles/:tileID", adminHandler.GetTile)
+	admin.PUT("/board/tiles/:tileID", adminHandler.UpdateTile)
+	admin.PUT("/board/tiles/:tileID/effect", adminHandler.UpdateEffect)
+	admin.DELETE("/board/tiles/:tileID", adminHandler.DeleteTile)
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"マスの追加", "POST", "/admin/board/tiles", `{"id": 7, "kind": "profit", "effect": {"type": "profit", "amount": 10}, "prev_ids": [6], "next_ids": [], "message": "マス7を追加"}`, http.StatusCreated, `"version":2`},
+		{"同じIDのマスの追加", "POST", "/admin/board/tiles", `{"id": 7, "kind": "normal", "prev_ids": [], "next_ids": []}`, http.StatusConflict, "error"},
+		{"不正なボディ", "POST", "/admin/board/tiles", `{`, http.StatusBadRequest, "error"},
+		{"マスの取得", "GET", "/admin/board/tiles/7", "", http.StatusOK, `"id":7`},
+		{"存在しないマスの取得", "GET", "/admin/board/tiles/99", "", http.StatusNotFound, "error"},
+		{"不正なマスID", "GET", "/admin/board/tiles/abc", "", http.StatusBadRequest, "error"},
+		{"存在しないマスの変更", "PUT", "/admin/board/tiles/99", `{"kind": "normal", "prev_ids": [], "next_ids": []}`, http.StatusNotFound, "error"},
+		{"検証でエラーになる効果", "PUT", "/admin/board/tiles/7/effect", `{"effect": {"type": "teleport"}}`, http.StatusUnprocessableEntity, "unknown_effect"},
+		{"効果の変更", "PUT", "/admin/board/tiles/7/effect", `{"effect": {"type": "loss", "amount": 5}}`, http.StatusOK, `"version":3`},
+		{"マスの変更", "PUT", "/admin/board/tiles/7", `{"kind": "normal", "effect": null, "prev_ids": [6], "next_ids": []}`, http.StatusOK, `"version":4`},
+		{"マスの削除", "DELETE", "/admin/board/tiles/7", "", http.StatusOK, `"version":5`},
+		{"存在しないマスの削除", "DELETE", "/admin/board/tiles/7", "", http.StatusNotFound, "error"},
+		{"版の一覧", "GET", "/admin/board/versions", "", http.StatusOK, `"author":"admin-user"`},
+		{"版の取得", "GET", "/admin/board/versions/2", "", http.StatusOK, "マス7を追加"},
+		{"存在しない版の取得", "GET", "/admin/board/versions/99", "", http.StatusNotFound, "error"},
+		{"版の巻き戻し", "POST", "/admin/board/versions/2/rollback", "", http.StatusCreated, `"version":6`},
+		{"存在しない版への巻き戻し", "POST", "/admin/board/versions/99/rollback", "", http.StatusNotFound, "error"},
+		{"使う版の変更", "PUT", "/admin/board/active", `{"version": 6}`, http.StatusOK, `"version":6`},
+		{"存在しない版を使う", "PUT", "/admin/board/active", `{"version": 99}`, http.StatusNotFound, "error"},
+		{"最新の版のマスの一覧", "GET", "/admin/board/tiles", "", http.StatusOK, `"version":6`},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s: 期待されるステータスコード: %d, 実際: %d (%s)", tc.name, tc.wantStatus, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), tc.wantBody) {
+			t.Errorf("%s: レスポンスに %q が含まれていません: %s", tc.name, tc.wantBody, w.Body.String())
+		}
+	}
+
+	// 新しい部屋は使う版の盤面で始まる
+	r, err := rooms.Create("booth-a", room.Options{})
+	if err != nil {
+		t.Fatalf("部屋の作成に失敗: %v", err)
+	}
+	if r.Game.BoardVersion() != 6 || len(r.Game.Tiles()) != 7 {
+		t.Errorf("使う版の盤面で部屋が作られていません: version=%d tiles=%d", r.Game.BoardVersion(), len(r.Game.Tiles()))
+	}
+}